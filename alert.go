@@ -0,0 +1,108 @@
+package dotagiftx
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SellerAlertService provides access to seller alert service.
+type SellerAlertService interface {
+	// InventoryRemoved alerts the seller when removed inventory assets
+	// matches any of their live asks.
+	InventoryRemoved(steamID string, events []InventoryEvent) error
+
+	// ListingUnavailable alerts the seller when the live ask was marked as
+	// unavailable since the item was no longer found on their inventory.
+	ListingUnavailable(mkt Market) error
+}
+
+// NewSellerAlertService returns new seller alert service that posts alerts
+// on discord webhook.
+func NewSellerAlertService(us UserStorage, ms MarketStorage, wp webhookPoster) SellerAlertService {
+	return &sellerAlertService{us, ms, wp}
+}
+
+type sellerAlertService struct {
+	userStg       UserStorage
+	marketStg     MarketStorage
+	webhookPoster webhookPoster
+}
+
+func (s *sellerAlertService) InventoryRemoved(steamID string, events []InventoryEvent) error {
+	var removed []SteamAsset
+	for _, ev := range events {
+		if ev.Type == InventoryEventRemoved {
+			removed = append(removed, ev.Asset)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	seller, err := s.userStg.Get(steamID)
+	if err != nil {
+		return err
+	}
+	asks, err := s.marketStg.Find(FindOpts{
+		IndexKey: "user_id",
+		Filter: Market{
+			UserID: seller.ID,
+			Type:   MarketTypeAsk,
+			Status: MarketStatusLive,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, ask := range MatchRemovedAsks(asks, removed) {
+		content := fmt.Sprintf("[inventory removed] %s left the inventory but still listed, "+
+			"update or remove the listing to avoid getting reported", ask.Item.Name)
+		if err = s.post(*seller, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sellerAlertService) ListingUnavailable(mkt Market) error {
+	if mkt.User == nil || mkt.Item == nil {
+		return fmt.Errorf("missing data user:%#v item:%#v", mkt.User, mkt.Item)
+	}
+
+	content := fmt.Sprintf("[listing unavailable] %s was no longer found on the inventory, "+
+		"the listing was marked as unavailable", mkt.Item.Name)
+	return s.post(*mkt.User, content)
+}
+
+func (s *sellerAlertService) post(seller User, content string) error {
+	username := fmt.Sprintf("%s (%s)", seller.Name, seller.SteamID)
+	return s.webhookPoster.PostWebhook(username, content)
+}
+
+// MatchRemovedAsks returns live asks that matches the removed assets by item
+// name and its aliases, resells are skipped since the item was never on the
+// seller inventory.
+func MatchRemovedAsks(asks []Market, removed []SteamAsset) []Market {
+	var matches []Market
+	for _, ask := range asks {
+		if ask.Item == nil || ask.IsResell() {
+			continue
+		}
+
+		aliases := ask.Item.MatchAliases()
+		names := aliases.MatchNames(ask.Item.Name)
+		if slices.ContainsFunc(removed, func(a SteamAsset) bool {
+			if aliases.IsExcluded(a.Name) {
+				return false
+			}
+			return slices.ContainsFunc(names, func(n string) bool {
+				return strings.EqualFold(strings.TrimSpace(n), strings.TrimSpace(a.Name))
+			})
+		}) {
+			matches = append(matches, ask)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,85 @@
+package dotagiftx
+
+import (
+	"strings"
+	"testing"
+)
+
+type fakeAlertMarketStorage struct {
+	MarketStorage
+	markets []Market
+}
+
+func (s *fakeAlertMarketStorage) Find(o FindOpts) ([]Market, error) {
+	f := o.Filter.(Market)
+	var res []Market
+	for _, m := range s.markets {
+		if m.UserID == f.UserID && m.Type == f.Type && m.Status == f.Status {
+			res = append(res, m)
+		}
+	}
+	return res, nil
+}
+
+type fakeWebhookPoster []string
+
+func (p *fakeWebhookPoster) PostWebhook(username, content string) error {
+	*p = append(*p, username+": "+content)
+	return nil
+}
+
+func TestSellerAlertService_InventoryRemoved(t *testing.T) {
+	ask := func(id, name string) Market {
+		return Market{ID: id, UserID: "u1", Type: MarketTypeAsk, Status: MarketStatusLive, Item: &Item{Name: name}}
+	}
+	resell := ask("resell", "Sylvan Vedette")
+	yes := true
+	resell.Resell = &yes
+	userStg := &fakeUserStorage{users: map[string]User{"765": {ID: "u1", SteamID: "765", Name: "kudarap"}}}
+	marketStg := &fakeAlertMarketStorage{markets: []Market{
+		ask("sylvan", "Sylvan Vedette"),
+		ask("bundle", "Intergalactic Obliterator Bundle"),
+		ask("kept", "Dipper the Destroyer Bundle"),
+		resell,
+	}}
+	poster := &fakeWebhookPoster{}
+	svc := NewSellerAlertService(userStg, marketStg, poster)
+
+	err := svc.InventoryRemoved("765", []InventoryEvent{
+		{Type: InventoryEventRemoved, Asset: SteamAsset{Name: "Sylvan Vedette"}},
+		{Type: InventoryEventRemoved, Asset: SteamAsset{Name: "Intergalactic Orbliterator"}},
+		{Type: InventoryEventRemoved, Asset: SteamAsset{Name: "Golden Dipper the Destroyer"}},
+		{Type: InventoryEventAdded, Asset: SteamAsset{Name: "Dipper the Destroyer"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*poster) != 2 {
+		t.Fatalf("InventoryRemoved() alerts = %v, want 2", *poster)
+	}
+	for i, name := range []string{"Sylvan Vedette", "Intergalactic Obliterator Bundle"} {
+		if got := (*poster)[i]; !strings.HasPrefix(got, "kudarap (765)") || !strings.Contains(got, name) {
+			t.Errorf("InventoryRemoved() alert = %s, want %s of kudarap", got, name)
+		}
+	}
+}
+
+func TestSellerAlertService_ListingUnavailable(t *testing.T) {
+	poster := &fakeWebhookPoster{}
+	svc := NewSellerAlertService(nil, nil, poster)
+
+	if err := svc.ListingUnavailable(Market{ID: "m1"}); err == nil {
+		t.Errorf("ListingUnavailable() missing user and item should error")
+	}
+	err := svc.ListingUnavailable(Market{
+		ID:   "m1",
+		User: &User{Name: "kudarap", SteamID: "765"},
+		Item: &Item{Name: "Sylvan Vedette"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*poster) != 1 || !strings.HasPrefix((*poster)[0], "kudarap (765): [listing unavailable] Sylvan Vedette") {
+		t.Errorf("ListingUnavailable() alerts = %v", *poster)
+	}
+}
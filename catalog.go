@@ -1,6 +1,9 @@
 package dotagiftx
 
 import (
+	"cmp"
+	"fmt"
+	"slices"
 	"time"
 )
 
@@ -67,5 +70,84 @@ type (
 
 		// Trending returns a list if top 10 trending catalog.
 		Trending() ([]Catalog, error)
+
+		// Facets returns catalog counts of the find options keyword and filter.
+		Facets(FindOpts) (*CatalogFacets, error)
+	}
+
+	// CatalogFacets represents catalog counts by filter values of the current
+	// query. Hero, origin and rarity counts ignore their own filter so other
+	// values of the same field remains selectable.
+	CatalogFacets struct {
+		Heroes   []FacetCount `json:"heroes"`
+		Origins  []FacetCount `json:"origins"`
+		Rarities []FacetCount `json:"rarities"`
+		// Prices counts catalogs with offers by lowest ask price range.
+		Prices  []FacetCount `json:"prices"`
+		HasBids int          `json:"has_bids"`
+	}
+
+	// FacetCount represents number of catalogs with the value.
+	FacetCount struct {
+		Value string `json:"value"`
+		Count int    `json:"count"`
+	}
+
+	// CatalogFacetCounts represents catalog counts by filter values counted
+	// on the data store, prices are keyed by price range index.
+	CatalogFacetCounts struct {
+		Heroes   map[string]int
+		Origins  map[string]int
+		Rarities map[string]int
+		Prices   map[int]int
+		HasBids  int
 	}
 )
+
+// CatalogFacetFields are catalog fields needed to compose facets.
+var CatalogFacetFields = []string{"hero", "origin", "rarity", "lowest_ask", "bid_count"}
+
+// CatalogPriceBuckets are lower bounds of lowest ask price ranges, the last
+// range has no upper bound.
+var CatalogPriceBuckets = []float64{0, 1, 5, 10, 25, 50, 100}
+
+// NewCatalogFacets composes facets from counts of the data store, empty
+// values are skipped and every price range is listed.
+func NewCatalogFacets(c CatalogFacetCounts) *CatalogFacets {
+	f := &CatalogFacets{
+		Heroes:   facetCounts(c.Heroes),
+		Origins:  facetCounts(c.Origins),
+		Rarities: facetCounts(c.Rarities),
+		HasBids:  c.HasBids,
+	}
+	for i := range CatalogPriceBuckets {
+		f.Prices = append(f.Prices, FacetCount{catalogPriceLabel(i), c.Prices[i]})
+	}
+	return f
+}
+
+// facetCounts sorts values by count and name on ties.
+func facetCounts(m map[string]int) []FacetCount {
+	res := make([]FacetCount, 0, len(m))
+	for v, n := range m {
+		if v == "" {
+			continue
+		}
+		res = append(res, FacetCount{v, n})
+	}
+	slices.SortFunc(res, func(a, b FacetCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return res
+}
+
+// catalogPriceLabel returns price range label like "1-5" and "100+".
+func catalogPriceLabel(i int) string {
+	if i == len(CatalogPriceBuckets)-1 {
+		return fmt.Sprintf("%g+", CatalogPriceBuckets[i])
+	}
+	return fmt.Sprintf("%g-%g", CatalogPriceBuckets[i], CatalogPriceBuckets[i+1])
+}
@@ -0,0 +1,45 @@
+package dotagiftx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewCatalogFacets(t *testing.T) {
+	got := NewCatalogFacets(CatalogFacetCounts{
+		Heroes:   map[string]int{"Axe": 1, "Lina": 2, "Juggernaut": 1, "": 3},
+		Origins:  map[string]int{"Immortal Treasure I": 1},
+		Rarities: map[string]int{"mythical": 1, "immortal": 1},
+		Prices:   map[int]int{3: 1},
+		HasBids:  1,
+	})
+	want := &CatalogFacets{
+		Heroes:   []FacetCount{{"Lina", 2}, {"Axe", 1}, {"Juggernaut", 1}},
+		Origins:  []FacetCount{{"Immortal Treasure I", 1}},
+		Rarities: []FacetCount{{"immortal", 1}, {"mythical", 1}},
+		Prices: []FacetCount{
+			{"0-1", 0}, {"1-5", 0}, {"5-10", 0}, {"10-25", 1}, {"25-50", 0}, {"50-100", 0}, {"100+", 0},
+		},
+		HasBids: 1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewCatalogFacets() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_catalogPriceLabel(t *testing.T) {
+	tests := []struct {
+		bucket int
+		want   string
+	}{
+		{0, "0-1"},
+		{1, "1-5"},
+		{5, "50-100"},
+		{6, "100+"},
+	}
+	for _, tt := range tests {
+		if got := catalogPriceLabel(tt.bucket); got != tt.want {
+			t.Errorf("catalogPriceLabel(%d) = %s, want %s", tt.bucket, got, tt.want)
+		}
+	}
+}
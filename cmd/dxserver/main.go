@@ -18,6 +18,7 @@ import (
 	"github.com/kudarap/dotagiftx/phantasm"
 	"github.com/kudarap/dotagiftx/redis"
 	"github.com/kudarap/dotagiftx/rethink"
+	"github.com/kudarap/dotagiftx/search"
 	"github.com/kudarap/dotagiftx/steam"
 	"github.com/kudarap/dotagiftx/tracing"
 	"github.com/sirupsen/logrus"
@@ -123,6 +124,12 @@ func (app *application) setup() error {
 	itemStg := rethink.NewItem(rethinkClient)
 	marketStg := rethink.NewMarket(rethinkClient)
 	trackStg := rethink.NewTrack(rethinkClient)
+	searchIndex, err := setupSearch(rethinkClient, itemStg)
+	if err != nil {
+		return err
+	}
+	catalogStg = search.NewCatalogStorage(searchIndex, catalogStg)
+	itemStg = search.NewItemStorage(searchIndex, itemStg)
 
 	statsStg := rethink.NewStats(rethinkClient, app.contextLog("storage_stats"))
 	reportStg := rethink.NewReport(rethinkClient)
@@ -155,7 +162,10 @@ func (app *application) setup() error {
 	reportSvc := dotagiftx.NewReportService(reportStg, discordClient)
 	statsSvc := dotagiftx.NewStatsService(statsStg, trackStg)
 	hammerSvc := dotagiftx.NewHammerService(userStg, marketStg)
-	phantasmSvc := phantasm.NewService(app.config.Phantasm, redisClient, slogger)
+	phantasmSvc, err := phantasm.NewService(app.config.Phantasm, redisClient, slogger)
+	if err != nil {
+		return fmt.Errorf("could not setup phantasm service: %s", err)
+	}
 
 	// Server setup.
 	logSvc.Println("setting up http server...")
@@ -215,10 +225,12 @@ func newApp() *application {
 }
 
 func setupSteam(cfg steam.Config, rc *redis.Client) (*steam.Client, error) {
-	c, err := steam.New(cfg, rc)
+	c, err := steam.New(cfg, rc, nil)
 	if err != nil {
 		return nil, fmt.Errorf("could not setup steam client: %s", err)
 	}
+	// Package level steam requests shares the client limiter and hosts.
+	steam.SetDefault(c)
 
 	return c, nil
 }
@@ -282,6 +294,30 @@ func setupClickHouse(cfg clickhouse.Config) (c *clickhouse.Client, err error) {
 	return
 }
 
+// setupSearch loads search index from items and keeps it updated by item
+// and catalog change feeds, feeds start first so no change is missed and
+// changes are applied after the index loads.
+func setupSearch(rethinkClient *rethink.Client, itemStg dotagiftx.ItemStorage) (*search.Index, error) {
+	index := search.NewIndex(search.DefaultSynonyms())
+	if err := rethinkClient.ListenChangeFeed("item", index.ItemChange); err != nil {
+		return nil, fmt.Errorf("could not listen item changes: %s", err)
+	}
+	if err := rethinkClient.ListenChangeFeed("catalog", index.CatalogChange); err != nil {
+		return nil, fmt.Errorf("could not listen catalog changes: %s", err)
+	}
+
+	items, err := itemStg.Find(dotagiftx.FindOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("could not load search index: %s", err)
+	}
+	docs := make([]search.Document, len(items))
+	for i, itm := range items {
+		docs[i] = search.ItemDocument(itm)
+	}
+	index.Load(docs)
+	return index, nil
+}
+
 func setupChangeFeeds(rethinkClient *rethink.Client, clickhouseClient *clickhouse.Client) error {
 	ctx := context.Background()
 	err := rethinkClient.ListenChangeFeed("track", func(prev, next []byte) error {
@@ -5,15 +5,18 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/kudarap/dotagiftx"
 	"github.com/kudarap/dotagiftx/config"
+	"github.com/kudarap/dotagiftx/discord"
 	"github.com/kudarap/dotagiftx/logging"
 	"github.com/kudarap/dotagiftx/phantasm"
 	"github.com/kudarap/dotagiftx/redis"
 	"github.com/kudarap/dotagiftx/rethink"
-	"github.com/kudarap/dotagiftx/steaminvorg"
+	"github.com/kudarap/dotagiftx/steam"
+	_ "github.com/kudarap/dotagiftx/steaminvorg"
 	"github.com/kudarap/dotagiftx/tracing"
 	"github.com/kudarap/dotagiftx/verify"
 	"github.com/kudarap/dotagiftx/worker"
@@ -91,6 +94,12 @@ func (app *application) setup() error {
 
 	// External services setup.
 	logSvc.Println("setting up external services...")
+	steamClient, err := steam.New(app.config.Steam, redisClient, nil)
+	if err != nil {
+		return fmt.Errorf("could not setup steam client: %s", err)
+	}
+	// Package level steam requests shares the client limiter and hosts.
+	steam.SetDefault(steamClient)
 
 	// Storage inits.
 	logSvc.Println("setting up data stores...")
@@ -98,7 +107,10 @@ func (app *application) setup() error {
 	marketStg := rethink.NewMarket(rethinkClient)
 	deliveryStg := rethink.NewDelivery(rethinkClient)
 	inventoryStg := rethink.NewInventory(rethinkClient)
+	snapshotStg := rethink.NewSnapshot(rethinkClient)
 	userStg := rethink.NewUser(rethinkClient)
+	statsStg := rethink.NewStats(rethinkClient, app.contextLog("storage_stats"))
+	itemStg := rethink.NewItem(rethinkClient)
 	queue := rethink.NewQueue(rethinkClient)
 
 	// Service inits.
@@ -106,14 +118,51 @@ func (app *application) setup() error {
 	logSvc.Println("setting up services...")
 	inventorySvc := dotagiftx.NewInventoryService(inventoryStg, marketStg, catalogStg)
 	deliverySvc := dotagiftx.NewDeliveryService(deliveryStg, marketStg)
-	phantasmSvc := phantasm.NewService(app.config.Phantasm, redisClient, slogger)
-	assetSource := verify.NewSource(
-		phantasmSvc.InventoryAssetWithProvider,
-		steaminvorg.InventoryAssetWithProvider,
+	// Worker only syncs persona names and never saves profile images.
+	userSvc := dotagiftx.NewUserService(userStg, nil, nil)
+	// Worker market service only updates user rank scores.
+	marketSvc := dotagiftx.NewMarketService(
+		marketStg, userStg, itemStg, nil, catalogStg, statsStg,
+		deliverySvc, inventorySvc, steamClient, nil, app.contextLog("service_market"),
 	)
+	sellerAlertSvc := dotagiftx.NewSellerAlertService(userStg, marketStg, discord.New(app.config.DiscordWebhookURL))
+	phantasmSvc, err := phantasm.NewService(app.config.Phantasm, redisClient, slogger)
+	if err != nil {
+		return fmt.Errorf("could not setup phantasm service: %s", err)
+	}
+	// Inventory providers registers themselves on import and only the ones
+	// set on config are created from the setup with its order and weight.
+	assetSource, err := verify.NewProviderSource(app.config.Verify, dotagiftx.InventoryProviderSetup{
+		app.config.Phantasm,
+		app.config.Steaminvorg,
+		phantasmSvc,
+		steamClient,
+		redisClient,
+	})
+	if err != nil {
+		return fmt.Errorf("could not setup asset source: %s", err)
+	}
+	assetSource.SetLogger(app.contextLog("verify"))
+	// Sellers gets alerted when removed assets are still listed.
+	snapshotLog := app.contextLog("inventory_snapshot")
+	assetSource.SetSnapshots(dotagiftx.NewInventorySnapshotService(snapshotStg), func(steamID string, events []dotagiftx.InventoryEvent, err error) {
+		if err != nil {
+			snapshotLog.Errorf("could not record inventory snapshot of %s: %s", steamID, err)
+		}
+		for _, ev := range events {
+			if ev.Type == dotagiftx.InventoryEventRemoved {
+				snapshotLog.Println(steamID, ev.Type, ev.Asset.Name, ev.Asset.Qty)
+			}
+		}
+		go func() {
+			if err := sellerAlertSvc.InventoryRemoved(steamID, events); err != nil {
+				snapshotLog.Errorf("could not alert seller %s: %s", steamID, err)
+			}
+		}()
+	})
 
 	// Setup application worker
-	tp := worker.NewTaskProcessor(time.Second, queue, inventorySvc, deliverySvc, assetSource, phantasmSvc)
+	tp := worker.NewTaskProcessor(time.Second, queue, marketStg, itemStg, inventorySvc, deliverySvc, assetSource, assetSource)
 	app.worker = worker.New(tp)
 	app.worker.SetLogger(app.contextLog("worker"))
 	app.worker.AddJob(jobs.NewRecheckInventory(
@@ -128,6 +177,16 @@ func (app *application) setup() error {
 		assetSource,
 		logging.WithPrefix(logger, "job_verify_inventory"),
 	))
+	app.worker.AddJob(jobs.NewWatchLiveInventory(
+		inventorySvc,
+		marketStg,
+		catalogStg,
+		marketSvc,
+		sellerAlertSvc,
+		assetSource,
+		redisClient,
+		logging.WithPrefix(logger, "job_watch_live_inventory"),
+	))
 	app.worker.AddJob(jobs.NewVerifyDelivery(
 		deliverySvc,
 		marketStg,
@@ -158,6 +217,14 @@ func (app *application) setup() error {
 		redisClient,
 		logging.WithPrefix(logger, "job_expiring_market"),
 	))
+	app.worker.AddJob(jobs.NewSyncSteamProfile(
+		userSvc,
+		marketStg,
+		steamClient,
+		redisClient,
+		logging.WithPrefix(logger, "job_sync_steam_profile"),
+	))
+	app.worker.AddJob(jobs.NewProviderHealth(assetSource, redisClient, logging.WithPrefix(logger, "job_provider_health")))
 	app.worker.AddJob(jobs.NewSweepMarket(marketStg, logging.WithPrefix(logger, "job_sweep_market")))
 	app.worker.AddJob(jobs.NewSweepPhantasmCache(phantasmSvc, logging.WithPrefix(logger, "job_sweep_phantasm")))
 
@@ -180,9 +247,9 @@ func (app *application) setup() error {
 func (app *application) run() error {
 	defer app.closerFn()
 
-	// Handle quit on SIGINT (CTRL-C).
+	// Handle quit on SIGINT (CTRL-C) and SIGTERM.
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
 	go app.worker.Start()
 
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/config"
+	"github.com/kudarap/dotagiftx/phantasm"
+)
+
+const (
+	configPrefix = "DG"
+
+	readTimeout     = time.Second * 10
+	shutdownTimeout = time.Minute
+)
+
+var logger = slog.Default()
+
+func main() {
+	v := dotagiftx.NewVersion(false, tag, commit, built)
+	logger.Info("starting phantasmd", "version", v.Tag, "hash", v.Commit, "built", v.Built)
+
+	config.EnvPrefix = configPrefix
+	var conf config.Config
+	if err := config.Load(&conf); err != nil {
+		logger.Error("could not load config", "err", err)
+		os.Exit(1)
+	}
+
+	daemon, err := phantasm.NewDaemon(conf.Phantasm, logger)
+	if err != nil {
+		logger.Error("could not setup daemon", "err", err)
+		os.Exit(1)
+	}
+
+	if err = run(daemon); err != nil {
+		logger.Error("could not run", "err", err)
+		os.Exit(1)
+	}
+	logger.Info("stopped!")
+}
+
+func run(daemon *phantasm.Daemon) error {
+	conf := daemon.Config()
+	srv := &http.Server{
+		Addr:        conf.Addr,
+		Handler:     daemon.Handler(),
+		ReadTimeout: readTimeout,
+		// Crawl response is written after the crawl is done.
+		WriteTimeout: conf.CrawlTimeout + readTimeout,
+	}
+
+	// Handle error on server start.
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Info(fmt.Sprintf("server running on %s", srv.Addr))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+
+	// Handle quit on SIGINT (CTRL-C) and SIGTERM.
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-quit:
+		// Let in-flight crawls finish and post to webhook.
+		logger.Info("server shutting down...")
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(ctx)
+	}
+}
+
+// version details used by ldflags.
+var tag, commit, built string
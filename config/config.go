@@ -13,6 +13,8 @@ import (
 	"github.com/kudarap/dotagiftx/redis"
 	"github.com/kudarap/dotagiftx/rethink"
 	"github.com/kudarap/dotagiftx/steam"
+	"github.com/kudarap/dotagiftx/steaminvorg"
+	"github.com/kudarap/dotagiftx/verify"
 )
 
 // EnvPrefix default env prefix APP.
@@ -36,6 +38,8 @@ type Config struct {
 	Paypal              paypal.Config
 	Log                 logging.Config
 	Phantasm            phantasm.Config
+	Verify              verify.Config
+	Steaminvorg         steaminvorg.Config
 	DiscordWebhookURL   string `envconfig:"DISCORD_WEBHOOK_URL"`
 }
 
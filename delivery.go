@@ -3,8 +3,11 @@ package dotagiftx
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -50,10 +53,18 @@ const (
 	// 3. Item might come from another source
 	DeliveryStatusNameVerified DeliveryStatus = 200
 
-	// DeliveryStatusSenderVerified both item existence and gift information matched the seller's avatar name. We could
-	// also use the date received to check against delivery data to strengthen its validity.
+	// DeliveryStatusBackdated item and gift information matched the seller's avatar name, but the gift date was
+	// before the market reservation which could mean the item was gifted from a previous transaction.
+	DeliveryStatusBackdated DeliveryStatus = 250
+
+	// DeliveryStatusSenderVerified both item existence and gift information matched the seller's avatar name, and the
+	// gift date was after the market reservation when both are available.
 	DeliveryStatusSenderVerified DeliveryStatus = 300
 
+	// DeliveryStatusTransferVerified sender verified and the delivered item matches the class of the seller's assets
+	// snapshot on listing, and those assets no longer exist on the seller's inventory.
+	DeliveryStatusTransferVerified DeliveryStatus = 350
+
 	// DeliveryStatusPrivate buyer's inventory is not visible to the public, and we can do nothing about it.
 	DeliveryStatusPrivate DeliveryStatus = 400
 
@@ -69,6 +80,10 @@ const (
 	// InventoryStatusVerified item exists on inventory base on the item name challenge.
 	InventoryStatusVerified InventoryStatus = 200
 
+	// InventoryStatusOverQuantity item exists on inventory, but the seller has more live listings of the item than
+	// the quantity owned, and this listing is beyond it.
+	InventoryStatusOverQuantity InventoryStatus = 300
+
 	// InventoryStatusPrivate buyer's inventory is not visible to the public, and we can do nothing about it.
 	InventoryStatusPrivate InventoryStatus = 400
 
@@ -76,24 +91,63 @@ const (
 	InventoryStatusError InventoryStatus = 500
 )
 
+// Verification evidence kinds.
+const (
+	EvidenceProvider        EvidenceKind = "provider"
+	EvidenceNameMatched     EvidenceKind = "name_matched"
+	EvidenceNoHit           EvidenceKind = "no_hit"
+	EvidenceVariantExcluded EvidenceKind = "variant_excluded"
+	EvidenceGiftable        EvidenceKind = "giftable"
+	EvidenceNotGiftable     EvidenceKind = "not_giftable"
+	EvidenceQuantity        EvidenceKind = "quantity"
+	EvidenceOverQuantity    EvidenceKind = "over_quantity"
+	EvidenceSenderMatched   EvidenceKind = "sender_matched"
+	EvidenceSenderUnmatched EvidenceKind = "sender_unmatched"
+	EvidenceGiftOpened      EvidenceKind = "gift_opened"
+	EvidenceGiftWrapped     EvidenceKind = "gift_wrapped"
+	EvidenceGiftDate        EvidenceKind = "gift_date"
+	EvidenceBackdated       EvidenceKind = "backdated"
+	EvidenceTransferred     EvidenceKind = "transferred"
+	EvidenceNotTransferred  EvidenceKind = "not_transferred"
+	EvidencePrivate         EvidenceKind = "private"
+	EvidenceAppeared        EvidenceKind = "appeared"
+	EvidencePreexisting     EvidenceKind = "preexisting"
+)
+
+// overQuantityEvidenceWeight lowers the confidence of listing beyond the owned quantity.
+const overQuantityEvidenceWeight = -50
+
 type (
+	// EvidenceKind represents verification evidence kind.
+	EvidenceKind string
+
+	// VerifyEvidence represents a finding of the verification process that
+	// explains its status, weight is its contribution to the confidence score.
+	VerifyEvidence struct {
+		Kind   EvidenceKind `json:"kind"   db:"kind"`
+		Text   string       `json:"text"   db:"text"`
+		Weight int          `json:"weight" db:"weight"`
+	}
+
 	// DeliveryStatus represents delivery status.
 	DeliveryStatus uint
 
 	// Delivery represents steam inventory delivery.
 	Delivery struct {
-		ID               string         `json:"id"                 db:"id,omitempty,omitempty"`
-		MarketID         string         `json:"market_id"          db:"market_id,omitempty,indexed" valid:"required"`
-		BuyerConfirmed   *bool          `json:"buyer_confirmed"    db:"buyer_confirmed,omitempty"`
-		BuyerConfirmedAt *time.Time     `json:"buyer_confirmed_at" db:"buyer_confirmed_at,omitempty"`
-		GiftOpened       *bool          `json:"gift_opened"        db:"gift_opened,omitempty"`
-		Status           DeliveryStatus `json:"status"             db:"status,omitempty,indexed"    valid:"required"`
-		Assets           []SteamAsset   `json:"steam_assets"       db:"steam_assets,omitempty"`
-		Retries          int            `json:"retries"            db:"retries,omitempty"`
-		VerifiedBy       string         `json:"verified_by"        db:"verified_by,omitempty,indexed"`
-		ElapsedMs        int64          `json:"elapsed_ms"         db:"elapsed_ms,omitempty,indexed"`
-		CreatedAt        *time.Time     `json:"created_at"         db:"created_at,omitempty,indexed,omitempty"`
-		UpdatedAt        *time.Time     `json:"updated_at"         db:"updated_at,omitempty,indexed,omitempty"`
+		ID               string           `json:"id"                 db:"id,omitempty,omitempty"`
+		MarketID         string           `json:"market_id"          db:"market_id,omitempty,indexed" valid:"required"`
+		BuyerConfirmed   *bool            `json:"buyer_confirmed"    db:"buyer_confirmed,omitempty"`
+		BuyerConfirmedAt *time.Time       `json:"buyer_confirmed_at" db:"buyer_confirmed_at,omitempty"`
+		GiftOpened       *bool            `json:"gift_opened"        db:"gift_opened,omitempty"`
+		Status           DeliveryStatus   `json:"status"             db:"status,omitempty,indexed"    valid:"required"`
+		Assets           []SteamAsset     `json:"steam_assets"       db:"steam_assets,omitempty"`
+		Retries          int              `json:"retries"            db:"retries,omitempty"`
+		VerifiedBy       string           `json:"verified_by"        db:"verified_by,omitempty,indexed"`
+		Confidence       int              `json:"confidence"         db:"confidence"`
+		Evidence         []VerifyEvidence `json:"evidence"           db:"evidence,omitempty"`
+		ElapsedMs        int64            `json:"elapsed_ms"         db:"elapsed_ms,omitempty,indexed"`
+		CreatedAt        *time.Time       `json:"created_at"         db:"created_at,omitempty,indexed,omitempty"`
+		UpdatedAt        *time.Time       `json:"updated_at"         db:"updated_at,omitempty,indexed,omitempty"`
 	}
 
 	// DeliveryService provides access to Delivery service.
@@ -137,16 +191,19 @@ type (
 
 	// Inventory represents steam inventory.
 	Inventory struct {
-		ID          string          `json:"id"           db:"id,omitempty,omitempty"`
-		MarketID    string          `json:"market_id"    db:"market_id,omitempty,indexed" valid:"required"`
-		Status      InventoryStatus `json:"status"       db:"status,omitempty,indexed"    valid:"required"`
-		Assets      []SteamAsset    `json:"steam_assets" db:"steam_assets,omitempty"`
-		Retries     int             `json:"retries"      db:"retries,omitempty"`
-		BundleCount int             `json:"bundle_count" db:"bundle_count,omitempty"`
-		VerifiedBy  string          `json:"verified_by"  db:"verified_by,omitempty,indexed"`
-		ElapsedMs   int64           `json:"elapsed_ms"   db:"elapsed_ms,omitempty,indexed"`
-		CreatedAt   *time.Time      `json:"created_at"   db:"created_at,omitempty,indexed,omitempty"`
-		UpdatedAt   *time.Time      `json:"updated_at"   db:"updated_at,omitempty,indexed,omitempty"`
+		ID          string           `json:"id"           db:"id,omitempty,omitempty"`
+		MarketID    string           `json:"market_id"    db:"market_id,omitempty,indexed" valid:"required"`
+		Status      InventoryStatus  `json:"status"       db:"status,omitempty,indexed"    valid:"required"`
+		Assets      []SteamAsset     `json:"steam_assets" db:"steam_assets,omitempty"`
+		Retries     int              `json:"retries"      db:"retries,omitempty"`
+		BundleCount int              `json:"bundle_count" db:"bundle_count,omitempty"`
+		Snapshot    []SteamAsset     `json:"snapshot"     db:"snapshot,omitempty"`
+		VerifiedBy  string           `json:"verified_by"  db:"verified_by,omitempty,indexed"`
+		Confidence  int              `json:"confidence"   db:"confidence"`
+		Evidence    []VerifyEvidence `json:"evidence"     db:"evidence,omitempty"`
+		ElapsedMs   int64            `json:"elapsed_ms"   db:"elapsed_ms,omitempty,indexed"`
+		CreatedAt   *time.Time       `json:"created_at"   db:"created_at,omitempty,indexed,omitempty"`
+		UpdatedAt   *time.Time       `json:"updated_at"   db:"updated_at,omitempty,indexed,omitempty"`
 	}
 
 	// InventoryService provides access to Inventory service.
@@ -257,17 +314,56 @@ func (i Inventory) CountBundles() (total int) {
 	return total
 }
 
+// Quantity returns number of matching assets owned.
+func (i Inventory) Quantity() (total int) {
+	for _, aa := range i.Assets {
+		total += max(aa.Qty, 1)
+	}
+	return total
+}
+
+// TakeSnapshot keeps the asset and class identifiers of the matching assets
+// on the first verified inventory, that will be used later to confirm the
+// ownership transfer on delivery.
+func (i Inventory) TakeSnapshot() *Inventory {
+	if len(i.Snapshot) != 0 || i.Status != InventoryStatusVerified {
+		return &i
+	}
+
+	for _, aa := range i.Assets {
+		i.Snapshot = append(i.Snapshot, SteamAsset{
+			AssetID:    aa.AssetID,
+			ClassID:    aa.ClassID,
+			InstanceID: aa.InstanceID,
+			Name:       aa.Name,
+			Type:       aa.Type,
+		})
+	}
+	return &i
+}
+
 // RetriesExceeded when it reached 5 retries.
 func (i Inventory) RetriesExceeded() bool {
 	return i.Retries > 3
 }
 
+// ConfidenceScore returns the sum of evidence weights bounded from 0 to 100.
+func ConfidenceScore(evidence []VerifyEvidence) int {
+	var score int
+	for _, e := range evidence {
+		score += e.Weight
+	}
+	return min(max(score, 0), 100)
+}
+
 var deliveryStatusTexts = map[DeliveryStatus]string{
-	DeliveryStatusNoHit:          "no hit",
-	DeliveryStatusNameVerified:   "name verified",
-	DeliveryStatusSenderVerified: "sender verified",
-	DeliveryStatusPrivate:        "private",
-	DeliveryStatusError:          "error",
+	DeliveryStatusNoHit:            "no hit",
+	DeliveryStatusNameVerified:     "name verified",
+	DeliveryStatusBackdated:        "backdated",
+	DeliveryStatusSenderVerified:   "sender verified",
+	DeliveryStatusTransferVerified: "transfer verified",
+	DeliveryStatusPrivate:          "private",
+	DeliveryStatusError:            "error",
 }
 
 // String returns text value of a delivery status.
@@ -281,10 +377,11 @@ func (s DeliveryStatus) String() string {
 }
 
 var inventoryStatusTexts = map[InventoryStatus]string{
-	InventoryStatusNoHit:    "no hit",
-	InventoryStatusVerified: "verified",
-	InventoryStatusPrivate:  "private",
-	InventoryStatusError:    "error",
+	InventoryStatusNoHit:        "no hit",
+	InventoryStatusVerified:     "verified",
+	InventoryStatusOverQuantity: "over quantity",
+	InventoryStatusPrivate:      "private",
+	InventoryStatusError:        "error",
 }
 
 // String returns text value of an inventory status.
@@ -444,6 +541,23 @@ func (s *inventoryService) Set(_ context.Context, inv *Inventory) error {
 		}
 	}()
 
+	// Flags listing beyond the owned quantity of the item.
+	if inv.Status == InventoryStatusVerified {
+		over, err := s.isOverQuantity(inv)
+		if err != nil {
+			return err
+		}
+		if over {
+			inv.Status = InventoryStatusOverQuantity
+			inv.Evidence = append(inv.Evidence, VerifyEvidence{
+				Kind:   EvidenceOverQuantity,
+				Text:   fmt.Sprintf("listing is beyond the owned quantity of %d", inv.Quantity()),
+				Weight: overQuantityEvidenceWeight,
+			})
+			inv.Confidence = ConfidenceScore(inv.Evidence)
+		}
+	}
+
 	// Update market Inventory status.
 	if err := s.marketStg.BaseUpdate(&Market{
 		ID:              inv.MarketID,
@@ -458,8 +572,59 @@ func (s *inventoryService) Set(_ context.Context, inv *Inventory) error {
 	if cur != nil {
 		inv.ID = cur.ID
 		inv.Retries = cur.Retries + 1
-		return s.inventoryStg.Update(inv)
+		inv.Snapshot = cur.Snapshot
+		return s.inventoryStg.Update(inv.TakeSnapshot())
 	}
 
-	return s.inventoryStg.Create(inv)
+	return s.inventoryStg.Create(inv.TakeSnapshot())
+}
+
+// isOverQuantity checks the listing position against the seller's live and
+// reserved listings of the same item, older listings are covered first.
+func (s *inventoryService) isOverQuantity(inv *Inventory) (bool, error) {
+	mkt, err := s.marketStg.Get(inv.MarketID)
+	if err != nil {
+		return false, err
+	}
+	if mkt.IsResell() {
+		return false, nil
+	}
+
+	var listings []Market
+	for _, status := range []MarketStatus{MarketStatusLive, MarketStatusReserved} {
+		res, err := s.marketStg.Find(FindOpts{
+			IndexKey: "user_id",
+			Filter: Market{
+				UserID: mkt.UserID,
+				ItemID: mkt.ItemID,
+				Type:   MarketTypeAsk,
+				Status: status,
+			},
+		})
+		if err != nil {
+			return false, err
+		}
+		listings = append(listings, res...)
+	}
+	listings = slices.DeleteFunc(listings, func(m Market) bool { return m.IsResell() })
+	slices.SortFunc(listings, compareListingAge)
+
+	pos := slices.IndexFunc(listings, func(m Market) bool { return m.ID == mkt.ID })
+	return pos >= inv.Quantity(), nil
+}
+
+// compareListingAge orders listings from oldest to newest, listings without
+// created_at goes last and ties are ordered by id.
+func compareListingAge(a, b Market) int {
+	switch {
+	case a.CreatedAt == nil && b.CreatedAt != nil:
+		return 1
+	case a.CreatedAt != nil && b.CreatedAt == nil:
+		return -1
+	case a.CreatedAt != nil && b.CreatedAt != nil:
+		if c := a.CreatedAt.Compare(*b.CreatedAt); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(a.ID, b.ID)
 }
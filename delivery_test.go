@@ -0,0 +1,29 @@
+package dotagiftx
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_compareListingAge(t *testing.T) {
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	listings := []Market{
+		{ID: "e"},
+		{ID: "d", CreatedAt: &t2},
+		{ID: "c"},
+		{ID: "b", CreatedAt: &t1},
+		{ID: "a", CreatedAt: &t2},
+	}
+	slices.SortFunc(listings, compareListingAge)
+
+	var got []string
+	for _, m := range listings {
+		got = append(got, m.ID)
+	}
+	want := []string{"b", "a", "d", "c", "e"}
+	if !slices.Equal(got, want) {
+		t.Errorf("compareListingAge() order = %v, want %v", got, want)
+	}
+}
@@ -20,6 +20,7 @@ const (
 	reportErrorIndex    = 5000
 	deliveryErrorIndex  = 6000
 	inventoryErrorIndex = 6100
+	snapshotErrorIndex  = 6200
 )
 
 var appErrorText = map[Errors]string{}
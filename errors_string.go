@@ -45,6 +45,8 @@ func _() {
 	_ = x[ReportErrNotFound-5000]
 	_ = x[ReportErrRequiredID-5001]
 	_ = x[ReportErrRequiredFields-5002]
+	_ = x[SnapshotErrNotFound-6200]
+	_ = x[SnapshotErrRequiredFields-6201]
 	_ = x[StorageUncaughtErr-100]
 	_ = x[StorageMergeErr-101]
 	_ = x[TrackErrNotFound-4000]
@@ -57,7 +59,7 @@ func _() {
 	_ = x[UserErrBanned-1106]
 }
 
-const _Errors_name = "StorageUncaughtErrStorageMergeErrAuthErrNotFoundAuthErrRequiredIDAuthErrRequiredFieldsAuthErrNoAccessAuthErrForbiddenAuthErrLoginAuthErrRefreshTokenUserErrNotFoundUserErrRequiredIDUserErrRequiredFieldsUserErrProfileImageDLUserErrSteamSyncUserErrSuspendedUserErrBannedItemErrNotFoundItemErrRequiredIDItemErrRequiredFieldsItemErrCreateItemExistsItemErrImportMarketErrNotFoundMarketErrRequiredIDMarketErrRequiredFieldsMarketErrInvalidStatusMarketErrNotesLimitMarketErrInvalidPriceMarketErrQtyLimitPerUserMarketErrRequiredPartnerURLMarketErrInvalidBidPriceMarketErrInvalidAskPriceCatalogErrNotFoundCatalogErrRequiredIDCatalogErrIndexingImageErrNotFoundImageErrUploadImageErrThumbnailTrackErrNotFoundReportErrNotFoundReportErrRequiredIDReportErrRequiredFieldsDeliveryErrNotFoundDeliveryErrRequiredIDDeliveryErrRequiredFieldsInventoryErrNotFoundInventoryErrRequiredIDInventoryErrRequiredFields"
+const _Errors_name = "StorageUncaughtErrStorageMergeErrAuthErrNotFoundAuthErrRequiredIDAuthErrRequiredFieldsAuthErrNoAccessAuthErrForbiddenAuthErrLoginAuthErrRefreshTokenUserErrNotFoundUserErrRequiredIDUserErrRequiredFieldsUserErrProfileImageDLUserErrSteamSyncUserErrSuspendedUserErrBannedItemErrNotFoundItemErrRequiredIDItemErrRequiredFieldsItemErrCreateItemExistsItemErrImportMarketErrNotFoundMarketErrRequiredIDMarketErrRequiredFieldsMarketErrInvalidStatusMarketErrNotesLimitMarketErrInvalidPriceMarketErrQtyLimitPerUserMarketErrRequiredPartnerURLMarketErrInvalidBidPriceMarketErrInvalidAskPriceCatalogErrNotFoundCatalogErrRequiredIDCatalogErrIndexingImageErrNotFoundImageErrUploadImageErrThumbnailTrackErrNotFoundReportErrNotFoundReportErrRequiredIDReportErrRequiredFieldsDeliveryErrNotFoundDeliveryErrRequiredIDDeliveryErrRequiredFieldsInventoryErrNotFoundInventoryErrRequiredIDInventoryErrRequiredFieldsSnapshotErrNotFoundSnapshotErrRequiredFields"
 
 var _Errors_map = map[Errors]string{
 	100:  _Errors_name[0:18],
@@ -107,6 +109,8 @@ var _Errors_map = map[Errors]string{
 	6100: _Errors_name[819:839],
 	6101: _Errors_name[839:861],
 	6102: _Errors_name[861:887],
+	6200: _Errors_name[887:906],
+	6201: _Errors_name[906:931],
 }
 
 func (i Errors) String() string {
@@ -13,6 +13,8 @@ type (
 		Limit         int
 		Fields        []string
 		WithMeta      bool
+		// IDs limits results to the ids, used by search index keyword matching.
+		IDs []string
 		// Advance options
 		IndexSorting bool // Use for sorting indexed field.
 		IndexKey     string
@@ -70,6 +70,7 @@ func (s *Server) privateRouter(r chi.Router) {
 			})
 		})
 		r.Post("/items", handleItemCreate(s.itemSvc, s.cache, s.divineKey))
+		r.Put("/items/{id}/aliases", handleItemAliases(s.itemSvc, s.cache, s.divineKey))
 		r.Post("/items_import", handleItemImport(s.itemSvc, s.cache, s.divineKey))
 		r.Post("/images", handleImageUpload(s.imageSvc))
 		r.Post("/reports", handleReportCreate(s.reportSvc))
@@ -77,5 +78,8 @@ func (s *Server) privateRouter(r chi.Router) {
 		r.Post("/hammer/suspend", handleHammerSuspend(s.hammerSvc, s.cache))
 		r.Post("/hammer/lift", handleHammerLift(s.hammerSvc, s.cache))
 		r.Post("/subscription", handleUserManualSubscription(s.userSvc, s.cache, s.divineKey))
+		r.Get("/users/{id}/personas", handleUserPersonas(s.userSvc, s.divineKey))
+		r.Get("/phantasm/crawlers", handlePhantasmCrawlers(s.phantasmSvc, s.divineKey))
+		r.Get("/verify/providers", handleVerifyProviders(s.cache, s.divineKey))
 	})
 }
@@ -13,8 +13,16 @@ const (
 	queryFlagRecentItems    = "recent"
 	queryFlagPopularItems   = "popular"
 	queryFlagRecentBidItems = "recent-bid"
+
+	// queryFlagFacets includes catalog facets of the query on the list.
+	queryFlagFacets = "facets"
 )
 
+type catalogListWithFacets struct {
+	dataWithMeta
+	Facets *dotagiftx.CatalogFacets `json:"facets"`
+}
+
 func handleMarketCatalogList(
 	svc dotagiftx.MarketService,
 	trackSvc dotagiftx.TrackService,
@@ -73,7 +81,15 @@ func handleMarketCatalogList(
 		}
 
 		// Save result to cache.
-		data := newDataWithMeta(list, md)
+		var data interface{} = newDataWithMeta(list, md)
+		if hasQueryField(r.URL, queryFlagFacets) {
+			facets, err := svc.CatalogFacets(opts)
+			if err != nil {
+				respondError(w, err)
+				return
+			}
+			data = catalogListWithFacets{newDataWithMeta(list, md), facets}
+		}
 		go func() {
 			if err := cache.Set(cacheKey, data, marketCacheExpr); err != nil {
 				logger.Errorf("could not save cache on catalog list: %s", err)
@@ -114,6 +114,31 @@ func handleItemCreate(svc dotagiftx.ItemService, cache cacheManager, divineKey s
 	}
 }
 
+func handleItemAliases(svc dotagiftx.ItemService, cache cacheManager, divineKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := isValidDivineKey(r, divineKey); err != nil {
+			respondError(w, err)
+			return
+		}
+
+		a := dotagiftx.ItemAliases{}
+		if err := parseForm(r, &a); err != nil {
+			respondError(w, err)
+			return
+		}
+
+		i, err := svc.UpdateAliases(r.Context(), chi.URLParam(r, "id"), a)
+		if err != nil {
+			respondError(w, err)
+			return
+		}
+
+		go cache.BulkDel(itemCacheKeyPrefix)
+
+		respondOK(w, i)
+	}
+}
+
 func handleItemImport(svc dotagiftx.ItemService, cache cacheManager, divineKey string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if err := isValidDivineKey(r, divineKey); err != nil {
@@ -11,8 +11,8 @@ import (
 func handlePhantasmWebhook(svc *phantasm.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := chi.URLParam(r, "steam_id")
-		secret := r.Header.Get(phantasm.WebhookAuthHeader)
-		if err := svc.SaveInventory(r.Context(), id, secret, r.Body); err != nil {
+		sig := phantasm.ParseWebhookSignature(r.Header)
+		if err := svc.SaveInventory(r.Context(), id, sig, r.Body); err != nil {
 			respondError(w, err)
 			return
 		}
@@ -39,3 +39,20 @@ func handlePhantasmCrawl() http.HandlerFunc {
 		respond(w, code, resp["body"])
 	}
 }
+
+func handlePhantasmCrawlers(svc *phantasm.Service, divineKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := isValidDivineKey(r, divineKey); err != nil {
+			respondError(w, err)
+			return
+		}
+
+		list, err := svc.Crawlers(r.Context())
+		if err != nil {
+			respondError(w, err)
+			return
+		}
+
+		respondOK(w, list)
+	}
+}
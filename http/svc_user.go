@@ -180,6 +180,28 @@ func handleUserSubscriptionWebhook(svc dotagiftx.UserService) http.HandlerFunc {
 	}
 }
 
+// handleUserPersonas returns steam persona name history of the user for admins.
+func handleUserPersonas(svc dotagiftx.UserService, divineKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := isValidDivineKey(r, divineKey); err != nil {
+			respondError(w, err)
+			return
+		}
+
+		u, err := svc.User(chi.URLParam(r, "id"))
+		if err != nil {
+			respondError(w, err)
+			return
+		}
+
+		personas := u.Personas
+		if personas == nil {
+			personas = []dotagiftx.UserPersona{}
+		}
+		respondOK(w, personas)
+	}
+}
+
 func handleUserManualSubscription(svc dotagiftx.UserService, cache cacheManager, divineKey string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if err := isValidDivineKey(r, divineKey); err != nil {
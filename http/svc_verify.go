@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/kudarap/dotagiftx/verify"
+)
+
+// handleVerifyProviders returns inventory provider stats last published by the worker.
+func handleVerifyProviders(cache cacheManager, divineKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := isValidDivineKey(r, divineKey); err != nil {
+			respondError(w, err)
+			return
+		}
+
+		list := []verify.ProviderStats{}
+		raw, err := cache.Get(verify.StatsCacheKey)
+		if err != nil {
+			respondError(w, err)
+			return
+		}
+		if raw != "" {
+			if err = json.UnmarshalFromString(raw, &list); err != nil {
+				respondError(w, err)
+				return
+			}
+		}
+
+		respondOK(w, list)
+	}
+}
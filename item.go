@@ -43,18 +43,31 @@ type (
 
 	// Item represents item information.
 	Item struct {
-		ID           string     `json:"id"           db:"id,omitempty"`
-		Slug         string     `json:"slug"         db:"slug,omitempty"        valid:"required"`
-		Name         string     `json:"name"         db:"name,omitempty"        valid:"required"`
-		Hero         string     `json:"hero"         db:"hero,omitempty"        valid:"required"`
-		Image        string     `json:"image"        db:"image,omitempty"`
-		Origin       string     `json:"origin"       db:"origin,omitempty"`
-		Rarity       string     `json:"rarity"       db:"rarity,omitempty"`
-		Contributors []string   `json:"-"            db:"contributors,omitempty"`
-		Active       *bool      `json:"active"       db:"active,omitempty"`
-		ViewCount    int        `json:"view_count"   db:"view_count,omitempty"`
-		CreatedAt    *time.Time `json:"created_at"   db:"created_at,omitempty"`
-		UpdatedAt    *time.Time `json:"updated_at"   db:"updated_at,omitempty"`
+		ID           string       `json:"id"           db:"id,omitempty"`
+		Slug         string       `json:"slug"         db:"slug,omitempty"        valid:"required"`
+		Name         string       `json:"name"         db:"name,omitempty"        valid:"required"`
+		Hero         string       `json:"hero"         db:"hero,omitempty"        valid:"required"`
+		Image        string       `json:"image"        db:"image,omitempty"`
+		Origin       string       `json:"origin"       db:"origin,omitempty"`
+		Rarity       string       `json:"rarity"       db:"rarity,omitempty"`
+		Contributors []string     `json:"-"            db:"contributors,omitempty"`
+		Active       *bool        `json:"active"       db:"active,omitempty"`
+		ViewCount    int          `json:"view_count"   db:"view_count,omitempty"`
+		Aliases      *ItemAliases `json:"aliases"      db:"aliases,omitempty"`
+		CreatedAt    *time.Time   `json:"created_at"   db:"created_at,omitempty"`
+		UpdatedAt    *time.Time   `json:"updated_at"   db:"updated_at,omitempty"`
+	}
+
+	// ItemAliases represents alternate names used for matching the item on steam inventories.
+	//
+	// Names are alternate or previous names of the item, Misspellings are known
+	// misspelled names by Valve, Components are bundle part names that covers
+	// unbundled items and Excludes are variant names that should not match.
+	ItemAliases struct {
+		Names        []string `json:"names"        db:"names"`
+		Misspellings []string `json:"misspellings" db:"misspellings"`
+		Components   []string `json:"components"   db:"components"`
+		Excludes     []string `json:"excludes"     db:"excludes"`
 	}
 
 	// ItemImportResult represents import process result.
@@ -81,6 +94,9 @@ type (
 		// Import creates new item from yaml format.
 		Import(ctx context.Context, f io.Reader) (ItemImportResult, error)
 
+		// UpdateAliases replaces item aliases used by inventory verification.
+		UpdateAliases(ctx context.Context, id string, aliases ItemAliases) (*Item, error)
+
 		// TopOrigins returns a list of top origin/treasure base on view count.
 		TopOrigins() ([]string, error)
 
@@ -108,6 +124,9 @@ type (
 		// Update persists item changes to data store.
 		Update(*Item) error
 
+		// UpdateAliases replaces item aliases to data store.
+		UpdateAliases(id string, aliases ItemAliases) error
+
 		// IsItemExist returns an error if item already exists by name.
 		IsItemExist(name string) error
 
@@ -146,6 +165,74 @@ func (i Item) SetDefaults() *Item {
 	return &i
 }
 
+// itemAliasDefaults holds known aliases of items that are not yet maintained on data store.
+var itemAliasDefaults = map[string]ItemAliases{
+	"Intergalactic Obliterator": {Misspellings: []string{"Intergalactic Orbliterator"}},
+}
+
+// defaultItemAliases returns the default aliases of names containing a known
+// item name, eg. the misspelling of "Intergalactic Obliterator Bundle" is
+// "Intergalactic Orbliterator Bundle".
+func defaultItemAliases(name string) ItemAliases {
+	var a ItemAliases
+	for known, def := range itemAliasDefaults {
+		if !strings.Contains(name, known) {
+			continue
+		}
+		replace := func(s []string) []string {
+			var res []string
+			for _, v := range s {
+				res = append(res, strings.ReplaceAll(name, known, v))
+			}
+			return res
+		}
+		a.Names = append(a.Names, replace(def.Names)...)
+		a.Misspellings = append(a.Misspellings, replace(def.Misspellings)...)
+		a.Components = append(a.Components, def.Components...)
+		a.Excludes = append(a.Excludes, def.Excludes...)
+	}
+	return a
+}
+
+// MatchAliases returns item aliases combined with default aliases and rules:
+//   - bundle items matches the unbundled items without "Bundle" suffix
+//     eg. Dipper the Destroyer Bundle, The Abscesserator Bundle.
+//   - golden variant of the item is excluded.
+func (i Item) MatchAliases() ItemAliases {
+	var a ItemAliases
+	if i.Aliases != nil {
+		a = *i.Aliases
+	}
+	def := defaultItemAliases(i.Name)
+	name := strings.TrimSpace(strings.TrimSuffix(i.Name, "Bundle"))
+	a.Names = append(slices.Clone(a.Names), def.Names...)
+	a.Misspellings = append(slices.Clone(a.Misspellings), def.Misspellings...)
+	a.Components = append(slices.Clone(a.Components), def.Components...)
+	a.Excludes = append(slices.Clone(a.Excludes), def.Excludes...)
+	if name != i.Name {
+		a.Components = append(a.Components, name)
+		a.Misspellings = append(a.Misspellings, defaultItemAliases(name).Misspellings...)
+	}
+	a.Excludes = append(a.Excludes, "Golden "+name)
+	return a
+}
+
+// MatchNames returns all the names the item could be found as on steam inventories.
+func (a ItemAliases) MatchNames(name string) []string {
+	names := []string{name}
+	names = append(names, a.Names...)
+	names = append(names, a.Misspellings...)
+	names = append(names, a.Components...)
+	return slices.DeleteFunc(names, func(s string) bool { return strings.TrimSpace(s) == "" })
+}
+
+// IsExcluded checks asset name against excluded variant names.
+func (a ItemAliases) IsExcluded(assetName string) bool {
+	return slices.ContainsFunc(a.Excludes, func(s string) bool {
+		return strings.EqualFold(strings.TrimSpace(s), strings.TrimSpace(assetName))
+	})
+}
+
 func (i Item) ToCatalog() Catalog {
 	return Catalog{
 		ID:           i.ID,
@@ -298,6 +385,24 @@ func (s *itemService) Update(ctx context.Context, itm *Item) error {
 	return s.itemStg.Update(itm)
 }
 
+func (s *itemService) UpdateAliases(ctx context.Context, id string, aliases ItemAliases) (*Item, error) {
+	au := AuthFromContext(ctx)
+	if au == nil {
+		return nil, AuthErrNoAccess
+	}
+
+	itm, err := s.itemStg.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.itemStg.UpdateAliases(itm.ID, aliases); err != nil {
+		return nil, err
+	}
+
+	itm.Aliases = &aliases
+	return itm, nil
+}
+
 func (s *itemService) Import(ctx context.Context, f io.Reader) (ItemImportResult, error) {
 	var result ItemImportResult
 
@@ -1,6 +1,9 @@
 package dotagiftx
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func Test_makeSlug(t *testing.T) {
 	tests := []struct {
@@ -22,3 +25,30 @@ func Test_makeSlug(t *testing.T) {
 		})
 	}
 }
+
+func TestItem_MatchAliases(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"Intergalactic Obliterator", []string{
+			"Intergalactic Obliterator",
+			"Intergalactic Orbliterator",
+		}},
+		{"Intergalactic Obliterator Bundle", []string{
+			"Intergalactic Obliterator Bundle",
+			"Intergalactic Orbliterator Bundle",
+			"Intergalactic Orbliterator",
+			"Intergalactic Obliterator",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := Item{Name: tt.name}
+			got := i.MatchAliases().MatchNames(i.Name)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("MatchNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -70,6 +70,7 @@ const (
 	MarketStatusRemoved      MarketStatus = 500
 	MarketStatusCancelled    MarketStatus = 600
 	MarketStatusExpired      MarketStatus = 700
+	MarketStatusUnavailable  MarketStatus = 800 // live ask item is no longer on seller's inventory
 )
 
 // Market trending score rates.
@@ -101,6 +102,7 @@ type (
 		Notes          string       `json:"notes"            db:"notes,omitempty"`
 		CreatedAt      *time.Time   `json:"created_at"       db:"created_at,omitempty,indexed"`
 		UpdatedAt      *time.Time   `json:"updated_at"       db:"updated_at,omitempty,indexed"`
+		ReservedAt     *time.Time   `json:"reserved_at"      db:"reserved_at,omitempty"`
 
 		InventoryStatus InventoryStatus `json:"inventory_status" db:"inventory_status,omitempty,indexed"`
 		DeliveryStatus  DeliveryStatus  `json:"delivery_status"  db:"delivery_status,omitempty,indexed"`
@@ -147,6 +149,9 @@ type (
 		// Catalog returns a list of catalogs.
 		Catalog(opts FindOpts) ([]Catalog, *FindMetadata, error)
 
+		// CatalogFacets returns catalog counts by filter values of the query.
+		CatalogFacets(opts FindOpts) (*CatalogFacets, error)
+
 		// CatalogDetails returns catalog details by item id.
 		CatalogDetails(id string, opts FindOpts) (*Catalog, error)
 
@@ -209,6 +214,7 @@ var MarketStatusTexts = map[MarketStatus]string{
 	MarketStatusRemoved:      "removed",
 	MarketStatusCancelled:    "cancelled",
 	MarketStatusExpired:      "expired",
+	MarketStatusUnavailable:  "unavailable",
 }
 
 // CheckCreate validates field on creating new market.
@@ -253,6 +259,7 @@ func (m Market) CheckUpdate() error {
 func (m Market) SetDefaults() *Market {
 	m.Status = MarketStatusLive
 	m.Currency = defaultCurrency
+	m.ReservedAt = nil
 	m.Price = priceToTenths(m.Price)
 	if m.Type == 0 {
 		m.Type = MarketTypeAsk
@@ -265,6 +272,19 @@ func (m Market) IsResell() bool {
 	return m.Type == MarketTypeAsk && m.Resell != nil && *m.Resell
 }
 
+// SellerPersonas returns persona names the seller held around the delivery,
+// last update is used as an estimate of the gift date.
+func (m Market) SellerPersonas() []string {
+	if m.User == nil {
+		return nil
+	}
+	t := time.Now()
+	if m.UpdatedAt != nil {
+		t = *m.UpdatedAt
+	}
+	return m.User.PersonasAround(t)
+}
+
 // String returns text value of a market status.
 func (s MarketStatus) String() string {
 	t, ok := MarketStatusTexts[s]
@@ -432,7 +452,8 @@ func (s *marketService) Create(ctx context.Context, market *Market) error {
 
 		// Resells should not verify items.
 		if !market.IsResell() {
-			if _, err = s.taskProc.Queue(ctx, user.TaskPriorityQueue(), TaskTypeVerifyInventory, market); err != nil {
+			payload := NewVerifyInventoryPayload(market.ID)
+			if _, err = s.taskProc.Queue(ctx, user.TaskPriorityQueue(), TaskTypeVerifyInventory, payload); err != nil {
 				s.logger.Errorf("could not queue task: market id %s: %s", market.ID, err)
 			}
 		}
@@ -476,6 +497,13 @@ func (s *marketService) Update(ctx context.Context, market *Market) error {
 			return err
 		}
 	}
+	// Reservation time will be used to check delivered item's gift date
+	// and only the service sets it, client value will be ignored.
+	market.ReservedAt = nil
+	if market.Status == MarketStatusReserved && cur.Status != MarketStatusReserved {
+		t := time.Now()
+		market.ReservedAt = &t
+	}
 
 	// Append note to existing notes.
 	market.Notes = strings.TrimSpace(fmt.Sprintf("%s\n%s", cur.Notes, market.Notes))
@@ -507,12 +535,12 @@ func (s *marketService) Update(ctx context.Context, market *Market) error {
 		case MarketStatusReserved:
 			// Resells should not verify items.
 			if !market.IsResell() {
-				if _, err = s.taskProc.Queue(ctx, priority, TaskTypeVerifyInventory, market); err != nil {
+				if _, err = s.taskProc.Queue(ctx, priority, TaskTypeVerifyInventory, NewVerifyInventoryPayload(market.ID)); err != nil {
 					s.logger.Errorf("could not queue task: market id %s: %s", market.ID, err)
 				}
 			}
 		case MarketStatusSold:
-			if _, err = s.taskProc.Queue(ctx, priority, TaskTypeVerifyDelivery, market); err != nil {
+			if _, err = s.taskProc.Queue(ctx, priority, TaskTypeVerifyDelivery, NewVerifyDeliveryPayload(market.ID)); err != nil {
 				s.logger.Errorf("could not queue task: market id %s: %s", market.ID, err)
 			}
 		}
@@ -744,6 +772,11 @@ func (s *marketService) Catalog(opts FindOpts) ([]Catalog, *FindMetadata, error)
 	}, nil
 }
 
+func (s *marketService) CatalogFacets(opts FindOpts) (*CatalogFacets, error) {
+	opts.Keyword = strings.ReplaceAll(opts.Keyword, `\`, "")
+	return s.catalogStg.Facets(opts)
+}
+
 func (s *marketService) TrendingCatalog(opts FindOpts) ([]Catalog, *FindMetadata, error) {
 	res, err := s.catalogStg.Trending()
 	if err != nil {
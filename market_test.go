@@ -0,0 +1,75 @@
+package dotagiftx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kudarap/dotagiftx/logging"
+)
+
+type fakeMarketStorage struct {
+	MarketStorage
+	markets map[string]Market
+}
+
+func (s *fakeMarketStorage) Get(id string) (*Market, error) {
+	m, ok := s.markets[id]
+	if !ok {
+		return nil, MarketErrNotFound
+	}
+	return &m, nil
+}
+
+func (s *fakeMarketStorage) Update(m *Market) error {
+	cur := s.markets[m.ID]
+	cur.Status = m.Status
+	cur.Notes = m.Notes
+	// Mimics omitempty fields on storage update.
+	if m.ReservedAt != nil {
+		cur.ReservedAt = m.ReservedAt
+	}
+	s.markets[m.ID] = cur
+	return nil
+}
+
+func (s *fakeMarketStorage) Index(id string) (*Market, error) { return s.Get(id) }
+
+type fakeStatsStorage struct{ StatsStorage }
+
+func (fakeStatsStorage) CountMarketStatusV2(FindOpts) (*MarketStatusCount, error) {
+	return nil, errors.New("stats not available")
+}
+
+type fakeCatalogStorage struct{ CatalogStorage }
+
+func (fakeCatalogStorage) Index(string) (*Catalog, error) { return nil, nil }
+
+func TestMarketService_Update_reservedAt(t *testing.T) {
+	backdated := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	marketStg := &fakeMarketStorage{markets: map[string]Market{
+		"m1": {ID: "m1", UserID: "u1", ItemID: "i1", Type: MarketTypeAsk, Status: MarketStatusLive},
+	}}
+	userStg := &fakeUserStorage{users: map[string]User{"u1": {ID: "u1"}}}
+	svc := NewMarketService(
+		marketStg, userStg, nil, nil, fakeCatalogStorage{}, fakeStatsStorage{},
+		nil, nil, nil, nil, logging.Default(),
+	)
+
+	ctx := AuthToContext(context.Background(), &Auth{UserID: "u1"})
+	if err := svc.Update(ctx, &Market{ID: "m1", Notes: "updated", ReservedAt: &backdated}); err != nil {
+		t.Fatal(err)
+	}
+	if got := marketStg.markets["m1"].ReservedAt; got != nil {
+		t.Errorf("Update() reserved_at = %v, want client value ignored", got)
+	}
+}
+
+func TestMarket_SetDefaults_reservedAt(t *testing.T) {
+	backdated := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := Market{ReservedAt: &backdated}
+	if got := m.SetDefaults().ReservedAt; got != nil {
+		t.Errorf("SetDefaults() reserved_at = %v, want nil", got)
+	}
+}
@@ -0,0 +1,220 @@
+package phantasm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	BlobDriverLocal = "local"
+	BlobDriverS3    = "s3"
+
+	defaultBlobTTL = time.Hour * 24 * 30
+)
+
+var errBlobNotFound = errors.New("blob not found")
+
+// BlobStore stores raw inventories so crawl results are shared between
+// service instances. Blobs expire by the ttl given on write.
+type BlobStore interface {
+	// Put writes data on key that expires after the ttl.
+	Put(ctx context.Context, key string, data []byte, ttl time.Duration) error
+	// Get returns data of key, errBlobNotFound when missing or expired.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Touch extends the expiry of key.
+	Touch(ctx context.Context, key string, ttl time.Duration) error
+	// Delete removes the key, missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// List returns all blobs with their expiry.
+	List(ctx context.Context) ([]BlobInfo, error)
+}
+
+// BlobInfo represents a stored blob metadata.
+type BlobInfo struct {
+	Key       string
+	ExpiresAt time.Time
+}
+
+// BlobConfig represents raw inventory storage settings, local driver stores
+// on Config.Path and s3 driver works with any S3-compatible storage.
+type BlobConfig struct {
+	Driver    string
+	TTL       time.Duration
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string `envconfig:"ACCESS_KEY"`
+	SecretKey string `envconfig:"SECRET_KEY"`
+}
+
+func (c BlobConfig) setDefault() BlobConfig {
+	if c.Driver == "" {
+		c.Driver = BlobDriverLocal
+	}
+	if c.TTL <= 0 {
+		c.TTL = defaultBlobTTL
+	}
+	if c.Region == "" {
+		c.Region = "us-east-1"
+	}
+	return c
+}
+
+// NewBlobStore returns blob store of the configured driver.
+func NewBlobStore(c BlobConfig, path string) (BlobStore, error) {
+	switch c.Driver {
+	case BlobDriverLocal:
+		return NewLocalBlob(path)
+	case BlobDriverS3:
+		return NewS3Blob(c)
+	default:
+		return nil, fmt.Errorf("unknown blob driver %q", c.Driver)
+	}
+}
+
+// NewLocalBlob returns a blob store on local directory, expiry is kept as
+// the file modification time.
+func NewLocalBlob(dir string) (BlobStore, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	return &localBlob{dir}, nil
+}
+
+type localBlob struct {
+	dir string
+}
+
+func (b *localBlob) Put(_ context.Context, key string, data []byte, ttl time.Duration) error {
+	p, err := b.path(key)
+	if err != nil {
+		return err
+	}
+
+	// write on temp file first so readers never get a partial blob.
+	tmp, err := os.CreateTemp(b.dir, ".blob-*")
+	if err != nil {
+		return fmt.Errorf("create file: %s", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write: %s", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("close file: %s", err)
+	}
+	exp := time.Now().Add(ttl)
+	if err = os.Chtimes(tmp.Name(), exp, exp); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (b *localBlob) Get(_ context.Context, key string) ([]byte, error) {
+	p, err := b.path(key)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(p)
+	if os.IsNotExist(err) {
+		return nil, errBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(info.ModTime()) {
+		return nil, errBlobNotFound
+	}
+
+	data, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil, errBlobNotFound
+	}
+	return data, err
+}
+
+func (b *localBlob) Touch(_ context.Context, key string, ttl time.Duration) error {
+	p, err := b.path(key)
+	if err != nil {
+		return err
+	}
+	exp := time.Now().Add(ttl)
+	if err = os.Chtimes(p, exp, exp); os.IsNotExist(err) {
+		return errBlobNotFound
+	}
+	return err
+}
+
+func (b *localBlob) Delete(_ context.Context, key string) error {
+	p, err := b.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (b *localBlob) List(_ context.Context) ([]BlobInfo, error) {
+	files, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read dir: %s", err)
+	}
+
+	var blobs []BlobInfo
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			return nil, fmt.Errorf("%s file info: %s", file, err)
+		}
+		blobs = append(blobs, BlobInfo{file.Name(), info.ModTime()})
+	}
+	return blobs, nil
+}
+
+// path ensures that the key has no path separators or parent directory references.
+func (b *localBlob) path(key string) (string, error) {
+	if !validBlobKey(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(b.dir, key), nil
+}
+
+func validBlobKey(key string) bool {
+	return key != "" && !strings.HasPrefix(key, ".") &&
+		!strings.ContainsAny(key, `/\`) && !strings.Contains(key, "..")
+}
+
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(io.LimitReader(r, maxWebhookBodySize))
+}
@@ -1,17 +1,32 @@
 package phantasm
 
+import "time"
+
 const (
 	defaultConfigAddr       = "http://localhost:8000/crawler/phantasm"
 	defaultConfigWebhookURL = "http://localhost:8000/webhook/phantasm"
 	defaultConfigSecret     = "reality-rift"
 	defaultConfigPath       = "./.localdata/phantasm"
+	defaultWebhookMaxAge    = time.Minute * 5
 )
 
 type Config struct {
 	Addrs      []string
 	WebhookURL string `envconfig:"WEBHOOK_URL"`
-	Secret     string
-	Path       string
+	// Secret authenticates crawl requests sent to crawler functions.
+	Secret string
+	Path   string
+	// Keys are comma separated webhook signing keys in "id:secret" format,
+	// multiple active keys allows rotation.
+	Keys []string
+	// WebhookMaxAge rejects stale webhook payloads by its signed timestamp.
+	WebhookMaxAge time.Duration `envconfig:"WEBHOOK_MAX_AGE"`
+	// Quarantine is how long unhealthy crawler is excluded from election.
+	Quarantine time.Duration
+	// Blob stores raw inventories on local Path or S3-compatible storage.
+	Blob BlobConfig
+	// Daemon configures phantasmd standalone crawler.
+	Daemon DaemonConfig
 }
 
 func (c Config) setDefault() Config {
@@ -27,5 +42,23 @@ func (c Config) setDefault() Config {
 	if c.Path == "" {
 		c.Path = defaultConfigPath
 	}
+	if c.WebhookMaxAge <= 0 {
+		c.WebhookMaxAge = defaultWebhookMaxAge
+	}
+	if c.Quarantine <= 0 {
+		c.Quarantine = defaultQuarantine
+	}
+	c.Blob = c.Blob.setDefault()
 	return c
 }
+
+// webhookKeys returns the active webhook signing keys by id.
+func (c Config) webhookKeys() map[string][]byte {
+	keys := map[string][]byte{}
+	for _, k := range c.Keys {
+		if key, ok := parseWebhookKey(k); ok {
+			keys[key.ID] = key.Secret
+		}
+	}
+	return keys
+}
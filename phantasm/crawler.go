@@ -6,7 +6,9 @@ package phantasm
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -16,6 +18,7 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -23,27 +26,53 @@ import (
 const (
 	WebhookAuthHeader = "X-Require-Whisk-Auth"
 
+	// Webhook signature headers, signature is the hex encoded HMAC-SHA256 of
+	// the steam id, unix timestamp and hex encoded SHA256 of the body.
+	WebhookKeyIDHeader     = "X-Phantasm-Key-Id"
+	WebhookTimestampHeader = "X-Phantasm-Timestamp"
+	WebhookSignatureHeader = "X-Phantasm-Signature"
+
 	precheckLimit = 25
 	queryLimit    = 2000
 	requestDelay  = 1000 * time.Millisecond
 
-	// ex. https://steamcommunity.com/inventory/76561198088587178/570/2
-	steamURL = "https://steamcommunity.com/inventory/%s/570/2?count=%d&start_assetid=%s"
+	// maxIncrementalParts is the head pages to look for the known asset
+	// before falling back to full refetch.
+	maxIncrementalParts = 2
 )
 
-var (
-	webhookURL string
-	secret     string
-)
+// ex. https://steamcommunity.com/inventory/76561198088587178/570/2
+var steamURL = "https://steamcommunity.com/inventory/%s/570/2?count=%d&start_assetid=%s"
+
+// webhookKey represents a webhook signing key in "id:secret" format.
+type webhookKey struct {
+	ID     string
+	Secret []byte
+}
+
+func parseWebhookKey(s string) (webhookKey, bool) {
+	id, secret, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok || id == "" || secret == "" {
+		return webhookKey{}, false
+	}
+	return webhookKey{id, []byte(secret)}, true
+}
+
+// SignWebhook returns the webhook payload signature.
+func SignWebhook(secret []byte, steamID string, timestamp int64, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(steamID + "\n" + strconv.FormatInt(timestamp, 10) + "\n" + hex.EncodeToString(bodyHash[:])))
+	return hex.EncodeToString(mac.Sum(nil))
+}
 
 func Main(args map[string]interface{}) map[string]interface{} {
 	log.Println("starting phantasm...")
-	if err := loadConfig(); err != nil {
+	crawler, err := loadConfig()
+	if err != nil {
 		return resp(http.StatusInternalServerError, err)
 	}
 
-	ctx := context.Background()
-	now := time.Now()
 	id, ok := args["steam_id"]
 	if !ok {
 		return resp(http.StatusBadRequest, "missing steam_id")
@@ -52,9 +81,62 @@ func Main(args map[string]interface{}) map[string]interface{} {
 	if !ok {
 		return resp(http.StatusBadRequest, "steam_id is not a string")
 	}
+	_, precheck := args["precheck"]
+	knownAssetID, _ := args["known_asset_id"].(string)
+	var knownCount int
+	switch v := args["known_count"].(type) {
+	case string:
+		knownCount, _ = strconv.Atoi(v)
+	case float64:
+		knownCount = int(v)
+	}
 
+	summary, status, err := crawler.Crawl(context.Background(), CrawlRequest{
+		SteamID:      steamID,
+		Precheck:     precheck,
+		KnownAssetID: knownAssetID,
+		KnownCount:   knownCount,
+	})
+	if err != nil {
+		return resp(status, err)
+	}
+	return resp(http.StatusOK, structToMap(summary))
+}
+
+// Crawler crawls steam inventory and posts it to the webhook url signed
+// by the signing key.
+type Crawler struct {
+	webhookURL string
+	signingKey webhookKey
+}
+
+// NewCrawler returns a new crawler, signing key is in "id:secret" format.
+func NewCrawler(webhookURL, signingKey string) (*Crawler, error) {
+	key, ok := parseWebhookKey(signingKey)
+	if webhookURL == "" || !ok {
+		return nil, errors.New("webhookURL and signing key required")
+	}
+	return &Crawler{webhookURL, key}, nil
+}
+
+// CrawlRequest represents crawl parameters, known asset and count of the
+// stored inventory enables incremental crawl.
+type CrawlRequest struct {
+	SteamID  string
+	Precheck bool
+	// KnownAssetID is the newest asset of the stored inventory.
+	KnownAssetID string
+	KnownCount   int
+}
+
+// Crawl fetches all inventory parts and posts it to webhook, precheck only
+// fetches the first part and returns its hash. With known asset, only new
+// assets are posted to be merged with the stored inventory. Status code is
+// the steam response status on failure.
+func (c *Crawler) Crawl(ctx context.Context, r CrawlRequest) (*CrawlSummary, int, error) {
+	now := time.Now()
+	steamID, precheck := r.SteamID, r.Precheck
 	limit := queryLimit
-	_, precheck := args["precheck"]
 	if precheck {
 		limit = precheckLimit
 	}
@@ -64,12 +146,33 @@ func Main(args map[string]interface{}) map[string]interface{} {
 	var inventoryCount int
 	var lastAssetID string
 	var invent *inventory
-	for {
+	if r.KnownAssetID != "" && !precheck {
+		log.Println("incremental - requesting parts until", r.KnownAssetID)
+		head, n, status, err := incremental(ctx, r)
+		if err != nil {
+			return nil, status, err
+		}
+		parts = n
+		if head == nil {
+			log.Println("incremental - inconsistent with known inventory, refetching all")
+		} else {
+			invent = head
+			inventoryCount = head.TotalInventoryCount
+		}
+	}
+	for invent == nil || invent.BaseAssetID == "" {
+		if parts > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, http.StatusGatewayTimeout, ctx.Err()
+			case <-time.After(requestDelay):
+			}
+		}
 		parts++
 		log.Println("requesting part...", parts)
 		next, status, err := get(ctx, steamID, limit, lastAssetID)
 		if err != nil {
-			return resp(status, err)
+			return nil, status, err
 		}
 
 		log.Println("merging inventories...")
@@ -80,7 +183,6 @@ func Main(args map[string]interface{}) map[string]interface{} {
 		if next.MoreItems == 0 || precheck {
 			break
 		}
-		time.Sleep(requestDelay)
 	}
 
 	var precheckHash string
@@ -88,13 +190,13 @@ func Main(args map[string]interface{}) map[string]interface{} {
 		log.Println("precheck - computing hash and skipping posting to webhook")
 		h, err := invent.hash(steamID)
 		if err != nil {
-			return resp(http.StatusInternalServerError, err)
+			return nil, http.StatusInternalServerError, err
 		}
 		precheckHash = h
 	} else {
-		if err := post(ctx, steamID, invent); err != nil {
+		if err := c.post(ctx, steamID, invent); err != nil {
 			log.Println("posting failed:", err)
-			return resp(http.StatusInternalServerError, err)
+			return nil, http.StatusInternalServerError, err
 		}
 	}
 
@@ -106,11 +208,76 @@ func Main(args map[string]interface{}) map[string]interface{} {
 	summary.Parts = parts
 	summary.InventoryCount = inventoryCount
 	summary.ElapsedSec = time.Since(now).Seconds()
-	summary.WebhookURL = webhookURL
+	summary.WebhookURL = c.webhookURL
 	summary.Precheck = precheck
 	summary.PrecheckHash = precheckHash
 	summary.LastAssetID = lastAssetID
-	return resp(http.StatusOK, structToMap(summary))
+	summary.Incremental = invent.BaseAssetID != ""
+	return &summary, http.StatusOK, nil
+}
+
+// incremental fetches head pages until the known asset, returns nil inventory
+// when the known asset is not found or removed assets are detected by count
+// which needs full refetch. The rest of the pages are still fetched for their
+// asset ids so the stored assets after the known asset can be verified since
+// an asset that left and another came in would still match the count.
+func incremental(ctx context.Context, r CrawlRequest) (inv *inventory, parts int, statusCode int, err error) {
+	var head *inventory
+	var lastAssetID string
+	for parts < maxIncrementalParts {
+		if parts > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, parts, http.StatusGatewayTimeout, ctx.Err()
+			case <-time.After(requestDelay):
+			}
+		}
+		parts++
+		next, status, err := get(ctx, r.SteamID, queryLimit, lastAssetID)
+		if err != nil {
+			return nil, parts, status, err
+		}
+
+		known := -1
+		for i, a := range next.Assets {
+			if a.AssetID == r.KnownAssetID {
+				known = i
+				break
+			}
+		}
+		if known < 0 {
+			if next.MoreItems == 0 {
+				return nil, parts, http.StatusOK, nil
+			}
+			head = merge(head, next)
+			lastAssetID = next.LastAssetID
+			continue
+		}
+
+		tail := assetIDs(next.Assets[known:])
+		next.Assets = next.Assets[:known]
+		head = merge(head, next)
+		if next.TotalInventoryCount != r.KnownCount+len(head.Assets) {
+			return nil, parts, http.StatusOK, nil
+		}
+		for next.MoreItems != 0 {
+			select {
+			case <-ctx.Done():
+				return nil, parts, http.StatusGatewayTimeout, ctx.Err()
+			case <-time.After(requestDelay):
+			}
+			parts++
+			next, status, err = get(ctx, r.SteamID, queryLimit, next.LastAssetID)
+			if err != nil {
+				return nil, parts, status, err
+			}
+			tail = append(tail, assetIDs(next.Assets)...)
+		}
+		head.BaseAssetID = r.KnownAssetID
+		head.TailAssetIDs = tail
+		return head, parts, http.StatusOK, nil
+	}
+	return nil, parts, http.StatusOK, nil
 }
 
 type CrawlSummary struct {
@@ -124,12 +291,17 @@ type CrawlSummary struct {
 	Precheck       bool    `json:"precheck"`
 	PrecheckHash   string  `json:"precheck_hash"`
 	LastAssetID    string  `json:"last_asset_id"`
+	Incremental    bool    `json:"incremental"`
 }
 
 type inventory struct {
 	Assets              []asset       `json:"assets"`
 	Descriptions        []description `json:"descriptions"`
 	TotalInventoryCount int           `json:"total_inventory_count"`
+	// BaseAssetID is the known asset that new assets are merged before and
+	// TailAssetIDs are the remote asset ids from the base asset.
+	BaseAssetID  string   `json:"base_assetid,omitempty"`
+	TailAssetIDs []string `json:"tail_assetids,omitempty"`
 
 	LastAssetID string `json:"last_assetid"`
 	MoreItems   int    `json:"more_items"`
@@ -216,6 +388,14 @@ func merge(res ...*inventory) *inventory {
 	return &inventory
 }
 
+func assetIDs(assets []asset) []string {
+	ids := make([]string, len(assets))
+	for i, a := range assets {
+		ids[i] = a.AssetID
+	}
+	return ids
+}
+
 func get(ctx context.Context, steamID string, count int, lastAssetID string) (i *inventory, statusCode int, err error) {
 	url := fmt.Sprintf(steamURL, steamID, count, lastAssetID)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -231,19 +411,22 @@ func get(ctx context.Context, steamID string, count int, lastAssetID string) (i
 	return &inv, statusCode, nil
 }
 
-func post(ctx context.Context, steamID string, data any) error {
+func (c *Crawler) post(ctx context.Context, steamID string, data any) error {
 	body, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 
-	url := strings.TrimRight(webhookURL, "/") + "/" + steamID
+	url := strings.TrimRight(c.webhookURL, "/") + "/" + steamID
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
 	if err != nil {
 		return err
 	}
+	ts := time.Now().Unix()
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set(WebhookAuthHeader, secret)
+	req.Header.Set(WebhookKeyIDHeader, c.signingKey.ID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(c.signingKey.Secret, steamID, ts, body))
 
 	if _, err = sendRequest(req, nil); err != nil {
 		return err
@@ -280,13 +463,11 @@ func resp(status int, body interface{}) map[string]interface{} {
 	}
 }
 
-func loadConfig() error {
-	webhookURL = os.Getenv("DG_PHANTASM_WEBHOOK_URL")
-	secret = os.Getenv("DG_PHANTASM_SECRET")
-	if webhookURL == "" || secret == "" {
-		return errors.New("webhookURL and secret required")
-	}
-	return nil
+// loadConfig signs with the first of the comma separated keys, new key
+// should be active on the service before it's rolled out on crawlers.
+func loadConfig() (*Crawler, error) {
+	first, _, _ := strings.Cut(os.Getenv("DG_PHANTASM_KEYS"), ",")
+	return NewCrawler(os.Getenv("DG_PHANTASM_WEBHOOK_URL"), first)
 }
 
 func structToMap(data interface{}) map[string]interface{} {
@@ -1,9 +1,14 @@
 package phantasm
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"slices"
+	"strconv"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -99,6 +104,87 @@ func Test_asset(t *testing.T) {
 	}
 }
 
+func TestCrawler_incremental(t *testing.T) {
+	// remote inventory is listed from the newest asset.
+	var remote []string
+	steam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		start := 0
+		if id := r.URL.Query().Get("start_assetid"); id != "" {
+			start = slices.Index(remote, id) + 1
+		}
+		end := min(start+count, len(remote))
+		page := inventory{TotalInventoryCount: len(remote)}
+		for _, id := range remote[start:end] {
+			page.Assets = append(page.Assets, asset{AssetID: id, ClassID: "c" + id, InstanceID: "0"})
+			page.Descriptions = append(page.Descriptions, description{ClassID: "c" + id, InstanceID: "0"})
+		}
+		if end < len(remote) {
+			page.MoreItems = 1
+			page.LastAssetID = remote[end-1]
+		}
+		fastjson.NewEncoder(w).Encode(page)
+	}))
+	defer steam.Close()
+	defer func(u string) { steamURL = u }(steamURL)
+	steamURL = steam.URL + "/inventory/%s/570/2?count=%d&start_assetid=%s"
+
+	var posted inventory
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted = inventory{}
+		fastjson.NewDecoder(r.Body).Decode(&posted)
+	}))
+	defer webhook.Close()
+	crawler, err := NewCrawler(webhook.URL, "test:secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored := []string{"5", "4", "3", "2", "1"}
+	tests := []struct {
+		name            string
+		remote          []string
+		wantIncremental bool
+		wantPosted      []string
+		wantTail        []string
+	}{
+		{"new assets", []string{"7", "6", "5", "4", "3", "2", "1"}, true, []string{"7", "6"}, stored},
+		{"unchanged", stored, true, nil, stored},
+		{"removed asset", []string{"6", "5", "4", "2", "1"}, false, []string{"6", "5", "4", "2", "1"}, nil},
+		{"known asset removed", []string{"6", "4", "3", "2", "1"}, false, []string{"6", "4", "3", "2", "1"}, nil},
+		{"one out one in", []string{"6", "5", "4", "3", "2", "0"}, true, []string{"6"}, []string{"5", "4", "3", "2", "0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote = tt.remote
+			summary, _, err := crawler.Crawl(context.Background(), CrawlRequest{
+				SteamID:      "76561198088587178",
+				KnownAssetID: stored[0],
+				KnownCount:   len(stored),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if summary.Incremental != tt.wantIncremental {
+				t.Errorf("Crawl() incremental = %t, want %t", summary.Incremental, tt.wantIncremental)
+			}
+			var got []string
+			for _, a := range posted.Assets {
+				got = append(got, a.AssetID)
+			}
+			if !slices.Equal(got, tt.wantPosted) {
+				t.Errorf("Crawl() posted assets = %v, want %v", got, tt.wantPosted)
+			}
+			if !slices.Equal(posted.TailAssetIDs, tt.wantTail) {
+				t.Errorf("Crawl() posted tail = %v, want %v", posted.TailAssetIDs, tt.wantTail)
+			}
+			if wantBase := map[bool]string{true: stored[0]}[tt.wantIncremental]; posted.BaseAssetID != wantBase {
+				t.Errorf("Crawl() posted base = %q, want %q", posted.BaseAssetID, wantBase)
+			}
+		})
+	}
+}
+
 func checkMissingAssetDesc(inv *inventory) []string {
 	var missing []string
 	for _, ass := range inv.Assets {
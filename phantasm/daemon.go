@@ -0,0 +1,226 @@
+package phantasm
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDaemonAddr          = ":8100"
+	defaultDaemonMaxConcurrent = 4
+	defaultDaemonCrawlTimeout  = time.Minute * 2
+
+	daemonRetryAfterSec = 5
+)
+
+// DaemonConfig represents phantasmd settings, the daemon serves the same
+// crawl API as the crawler function for hosts that can run a long-lived process.
+type DaemonConfig struct {
+	Addr string
+	// MaxConcurrent limits the number of crawls against steam at once.
+	MaxConcurrent int `envconfig:"MAX_CONCURRENT"`
+	// CrawlTimeout cancels a crawl that takes too long.
+	CrawlTimeout time.Duration `envconfig:"CRAWL_TIMEOUT"`
+}
+
+func (c DaemonConfig) setDefault() DaemonConfig {
+	if c.Addr == "" {
+		c.Addr = defaultDaemonAddr
+	}
+	if c.MaxConcurrent <= 0 {
+		c.MaxConcurrent = defaultDaemonMaxConcurrent
+	}
+	if c.CrawlTimeout <= 0 {
+		c.CrawlTimeout = defaultDaemonCrawlTimeout
+	}
+	return c
+}
+
+type crawlFunc func(ctx context.Context, r CrawlRequest) (*CrawlSummary, int, error)
+
+// Daemon serves crawl and precheck requests over http. Concurrent requests of
+// the same crawl parameters share a single crawl.
+type Daemon struct {
+	config DaemonConfig
+	secret string
+	crawl  crawlFunc
+	logger *slog.Logger
+
+	sem chan struct{}
+
+	mu       sync.Mutex
+	inflight map[string]*inflightCrawl
+}
+
+type inflightCrawl struct {
+	done    chan struct{}
+	summary *CrawlSummary
+	status  int
+	err     error
+}
+
+// NewDaemon returns a new crawler daemon that posts inventories to the
+// configured webhook signed with the first webhook key.
+func NewDaemon(config Config, logger *slog.Logger) (*Daemon, error) {
+	config = config.setDefault()
+	if len(config.Keys) == 0 {
+		return nil, errors.New("webhook signing key required")
+	}
+	crawler, err := NewCrawler(config.WebhookURL, config.Keys[0])
+	if err != nil {
+		return nil, err
+	}
+	return newDaemon(config.Daemon, config.Secret, crawler.Crawl, logger), nil
+}
+
+func newDaemon(config DaemonConfig, secret string, fn crawlFunc, logger *slog.Logger) *Daemon {
+	config = config.setDefault()
+	return &Daemon{
+		config:   config,
+		secret:   secret,
+		crawl:    fn,
+		logger:   logger,
+		sem:      make(chan struct{}, config.MaxConcurrent),
+		inflight: map[string]*inflightCrawl{},
+	}
+}
+
+// Config returns the daemon settings with defaults applied.
+func (d *Daemon) Config() DaemonConfig { return d.config }
+
+// Handler returns the daemon http routes.
+func (d *Daemon) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /crawler/{id}", d.handleCrawl)
+	mux.HandleFunc("GET /healthz", d.handleHealth)
+	return mux
+}
+
+// DaemonHealth represents daemon health status.
+type DaemonHealth struct {
+	Status        string `json:"status"`
+	InFlight      int    `json:"in_flight"`
+	Running       int    `json:"running"`
+	MaxConcurrent int    `json:"max_concurrent"`
+}
+
+func (d *Daemon) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	d.mu.Lock()
+	inflight := len(d.inflight)
+	d.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, DaemonHealth{
+		Status:        "ok",
+		InFlight:      inflight,
+		Running:       len(d.sem),
+		MaxConcurrent: d.config.MaxConcurrent,
+	})
+}
+
+func (d *Daemon) handleCrawl(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("X-Require-Whisk-Auth")
+	if subtle.ConstantTimeCompare([]byte(auth), []byte(d.secret)) != 1 {
+		writeError(w, http.StatusUnauthorized, "invalid auth")
+		return
+	}
+	q := r.URL.Query()
+	req := CrawlRequest{
+		SteamID:      q.Get("steam_id"),
+		Precheck:     q.Has("precheck"),
+		KnownAssetID: q.Get("known_asset_id"),
+	}
+	if req.SteamID == "" {
+		writeError(w, http.StatusBadRequest, "missing steam_id")
+		return
+	}
+	req.KnownCount, _ = strconv.Atoi(q.Get("known_count"))
+
+	c, ok := d.acquire(req)
+	if !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(daemonRetryAfterSec))
+		writeError(w, http.StatusTooManyRequests, "too many crawls in progress")
+		return
+	}
+
+	select {
+	case <-c.done:
+	case <-r.Context().Done():
+		return
+	}
+	if c.err != nil {
+		writeError(w, c.status, c.err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, c.summary)
+}
+
+// acquire joins the in-flight crawl of the same request or starts a new one
+// when there is a free slot.
+func (d *Daemon) acquire(r CrawlRequest) (*inflightCrawl, bool) {
+	key := fmt.Sprintf("%s:%t:%s:%d", r.SteamID, r.Precheck, r.KnownAssetID, r.KnownCount)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if c, ok := d.inflight[key]; ok {
+		return c, true
+	}
+	select {
+	case d.sem <- struct{}{}:
+	default:
+		return nil, false
+	}
+
+	c := &inflightCrawl{done: make(chan struct{})}
+	d.inflight[key] = c
+	go d.run(key, c, r)
+	return c, true
+}
+
+// run crawls detached from the requests context so joined requests still
+// get the result when the first one disconnects.
+func (d *Daemon) run(key string, c *inflightCrawl, r CrawlRequest) {
+	defer func() {
+		d.mu.Lock()
+		delete(d.inflight, key)
+		d.mu.Unlock()
+		<-d.sem
+		close(c.done)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.CrawlTimeout)
+	defer cancel()
+
+	start := time.Now()
+	c.summary, c.status, c.err = d.crawl(ctx, r)
+	if c.err != nil && c.status < http.StatusBadRequest {
+		c.status = http.StatusInternalServerError
+	}
+	if errors.Is(c.err, context.DeadlineExceeded) {
+		c.status = http.StatusGatewayTimeout
+	}
+	d.logger.Info("crawl done",
+		"steam_id", r.SteamID,
+		"precheck", r.Precheck,
+		"known_asset_id", r.KnownAssetID,
+		"status", c.status,
+		"elapsed", time.Since(start),
+		"err", c.err,
+	)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = fastjson.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
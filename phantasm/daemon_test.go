@@ -0,0 +1,132 @@
+package phantasm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDaemon_crawl(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context, r CrawlRequest) (*CrawlSummary, int, error) {
+		calls.Add(1)
+		<-release
+		if r.SteamID == "private" {
+			return nil, http.StatusForbidden, errors.New("private inventory")
+		}
+		return &CrawlSummary{SteamID: r.SteamID, Precheck: r.Precheck}, http.StatusOK, nil
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	d := newDaemon(DaemonConfig{MaxConcurrent: 2}, "secret", fn, logger)
+	srv := httptest.NewServer(d.Handler())
+	defer srv.Close()
+
+	crawl := func(query, secret string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/crawler/local?"+query, nil)
+		req.Header.Set("X-Require-Whisk-Auth", secret)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res
+	}
+
+	if res := crawl("steam_id=1", "wrong"); res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("bad auth status = %d, want %d", res.StatusCode, http.StatusUnauthorized)
+	}
+	if res := crawl("", "secret"); res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("missing steam id status = %d, want %d", res.StatusCode, http.StatusBadRequest)
+	}
+
+	// Same steam id crawls are deduped and share the result.
+	var wg sync.WaitGroup
+	statuses := make([]int, 3)
+	for i := range statuses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = crawl("steam_id=1", "secret").StatusCode
+		}()
+	}
+	waitFor(t, func() bool { return inflightCount(d) == 1 })
+	// Gives joined requests time to reach the in-flight crawl.
+	time.Sleep(time.Millisecond * 50)
+	// Fills the remaining slot, next distinct crawl is rejected.
+	wg.Add(1)
+	var privateStatus int
+	go func() {
+		defer wg.Done()
+		privateStatus = crawl("steam_id=private", "secret").StatusCode
+	}()
+	waitFor(t, func() bool { return inflightCount(d) == 2 })
+	res := crawl("steam_id=2&precheck", "secret")
+	if res.StatusCode != http.StatusTooManyRequests || res.Header.Get("Retry-After") == "" {
+		t.Fatalf("over limit status = %d retry-after = %q", res.StatusCode, res.Header.Get("Retry-After"))
+	}
+
+	close(release)
+	wg.Wait()
+	for _, s := range statuses {
+		if s != http.StatusOK {
+			t.Errorf("deduped crawl status = %d, want %d", s, http.StatusOK)
+		}
+	}
+	if privateStatus != http.StatusForbidden {
+		t.Errorf("private crawl status = %d, want %d", privateStatus, http.StatusForbidden)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("crawl calls = %d, want 2", got)
+	}
+	if n := inflightCount(d); n != 0 {
+		t.Errorf("in-flight after done = %d, want 0", n)
+	}
+}
+
+func TestDaemon_timeout(t *testing.T) {
+	fn := func(ctx context.Context, _ CrawlRequest) (*CrawlSummary, int, error) {
+		<-ctx.Done()
+		return nil, 0, ctx.Err()
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	d := newDaemon(DaemonConfig{CrawlTimeout: time.Millisecond * 10}, "secret", fn, logger)
+
+	req := httptest.NewRequest(http.MethodPost, "/crawler/local?steam_id=1", nil)
+	req.Header.Set("X-Require-Whisk-Auth", "secret")
+	rec := httptest.NewRecorder()
+	d.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("timeout status = %d, want %d", rec.Code, http.StatusGatewayTimeout)
+	}
+
+	rec = httptest.NewRecorder()
+	d.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("healthz status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func inflightCount(d *Daemon) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.inflight)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second * 2)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+}
@@ -12,22 +12,28 @@
 // crawler.go
 //	- script is intended for serverless functions to work around with ip rate limits during peak usage.
 // 	- publishes raw inventory data to target webhook url.
+//
+// daemon.go
+//	- serves the same crawl API as a long-lived process, see cmd/phantasmd.
 
 package phantasm
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"os"
-	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/kudarap/dotagiftx"
 	"github.com/kudarap/dotagiftx/steam"
 )
 
@@ -36,13 +42,20 @@ const (
 	defaultRecrawlCD        = time.Minute * 10
 	defaultCrawlerCD        = time.Minute
 
-	maxWaitRetry = 5
+	// maxCrawlWait is how long to wait for other crawl in progress when the
+	// request context has no deadline.
+	maxCrawlWait = time.Second * 30
+
+	maxWebhookBodySize = 64 << 20
 )
 
 var (
 	errFileNotFound = fmt.Errorf("raw file not found")
 	errFileWaiting  = fmt.Errorf("waiting for file")
 
+	errWebhookSignature = errors.New("invalid webhook signature")
+	errInconsistent     = errors.New("incremental inventory is inconsistent with stored inventory")
+
 	fastjson = jsoniter.ConfigFastest
 )
 
@@ -57,55 +70,202 @@ type Service struct {
 	crawlerCooldown  time.Duration
 	inventoryHashTTL time.Duration
 
-	electedCrawlerID int
+	crawlers    *registry
+	webhookKeys map[string][]byte
+	blobs       BlobStore
+}
+
+// ProviderID is the inventory provider name of phantasm.
+const ProviderID = "phantasm"
+
+func init() {
+	dotagiftx.RegisterInventoryProviderFactory(ProviderID, newProvider)
 }
 
-func NewService(config Config, cd cooldown, logger *slog.Logger) *Service {
+// newProvider creates phantasm inventory provider from phantasm config and
+// cooldown store on the setup, service on the setup is shared as is.
+func newProvider(s dotagiftx.InventoryProviderSetup) (dotagiftx.InventoryProvider, error) {
+	if svc, ok := dotagiftx.SetupValue[*Service](s); ok {
+		return svc, nil
+	}
+	cd, ok := dotagiftx.SetupValue[cooldown](s)
+	if !ok {
+		return nil, errors.New("cooldown store required")
+	}
+	c, _ := dotagiftx.SetupValue[Config](s)
+	svc, err := NewService(c, cd, slog.Default())
+	if err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+func NewService(config Config, cd cooldown, logger *slog.Logger) (*Service, error) {
 	config = config.setDefault()
-	if err := os.MkdirAll(config.Path, 0750); err != nil {
-		panic(err)
+	blobs, err := NewBlobStore(config.Blob, config.Path)
+	if err != nil {
+		return nil, fmt.Errorf("could not setup blob store: %s", err)
 	}
 
 	return &Service{
-		id:               "phantasm",
+		id:               ProviderID,
 		config:           config,
 		cooldown:         cd,
 		recrawlCooldown:  defaultRecrawlCD,
 		crawlerCooldown:  defaultCrawlerCD,
 		inventoryHashTTL: defaultInventoryHashTTL,
+		crawlers:         newRegistry(cd, config.Addrs, config.Quarantine),
+		webhookKeys:      config.webhookKeys(),
+		blobs:            blobs,
 		logger:           logger.With("module", "phantasm"),
+	}, nil
+}
+
+// WebhookSignature represents signature headers of the posted inventory.
+type WebhookSignature struct {
+	KeyID     string
+	Timestamp string
+	Signature string
+}
+
+// ParseWebhookSignature returns webhook signature from request headers.
+func ParseWebhookSignature(h http.Header) WebhookSignature {
+	return WebhookSignature{
+		KeyID:     h.Get(WebhookKeyIDHeader),
+		Timestamp: h.Get(WebhookTimestampHeader),
+		Signature: h.Get(WebhookSignatureHeader),
 	}
 }
 
-func (s *Service) SaveInventory(ctx context.Context, steamID, secret string, body io.ReadCloser) error {
+func (s *Service) SaveInventory(ctx context.Context, steamID string, sig WebhookSignature, body io.ReadCloser) error {
 	// ensure that the filename has no path separators or parent directory references
 	if steamID == "" || strings.Contains(steamID, "/") || strings.Contains(steamID, "\\") ||
 		strings.Contains(steamID, "..") {
 		return errors.New("invalid steam id")
 	}
-	if secret != s.config.Secret {
-		return fmt.Errorf("invalid secret")
-	}
-
-	file, err := os.Create(s.filePath(steamID))
-	if err != nil {
-		return fmt.Errorf("open file: %s", err)
-	}
 	defer func() {
-		if err = file.Close(); err != nil {
-			s.logger.Error("close file", "error", err.Error())
-		}
-		if err = body.Close(); err != nil {
+		if err := body.Close(); err != nil {
 			s.logger.Error("close body", "error", err.Error())
 		}
 	}()
-	if _, err = io.Copy(file, body); err != nil {
-		return fmt.Errorf("copy: %s", err)
+
+	b, err := io.ReadAll(io.LimitReader(body, maxWebhookBodySize))
+	if err != nil {
+		return fmt.Errorf("read body: %s", err)
+	}
+	if err = s.verifyWebhook(ctx, steamID, sig, b); err != nil {
+		return err
+	}
+
+	if b, err = s.mergeIncremental(ctx, steamID, b); err != nil {
+		return err
+	}
+	if b, err = compress(b); err != nil {
+		return fmt.Errorf("compress: %s", err)
+	}
+	if err = s.blobs.Put(ctx, blobKey(steamID), b, s.config.Blob.TTL); err != nil {
+		return fmt.Errorf("put blob: %s", err)
+	}
+	// waiting crawls will fall back to stored inventory after their deadline.
+	if err = s.cooldown.PublishInventorySaved(ctx, steamID); err != nil {
+		s.logger.ErrorContext(ctx, "publish inventory saved", "steam_id", steamID, "err", err)
 	}
 
 	return nil
 }
 
+// mergeIncremental merges new assets of incremental crawl into the stored
+// inventory, inconsistent payload drops the stored inventory so the next
+// crawl refetches all.
+func (s *Service) mergeIncremental(ctx context.Context, steamID string, b []byte) ([]byte, error) {
+	if !bytes.Contains(b, []byte(`"base_assetid"`)) {
+		return b, nil
+	}
+	var head inventory
+	if err := fastjson.Unmarshal(b, &head); err != nil {
+		return nil, fmt.Errorf("unmarshal: %s", err)
+	}
+	if head.BaseAssetID == "" {
+		return b, nil
+	}
+
+	stored, err := s.localInventoryFile(ctx, steamID)
+	if err != nil && !errors.Is(err, errFileNotFound) {
+		return nil, err
+	}
+	merged, ok := mergeIncremental(stored, &head)
+	if !ok {
+		if err = s.blobs.Delete(ctx, blobKey(steamID)); err != nil {
+			return nil, err
+		}
+		return nil, errInconsistent
+	}
+	return fastjson.Marshal(merged)
+}
+
+// mergeIncremental puts new assets before the base asset of the stored
+// inventory, stored assets from the base should still be on the remote
+// inventory and merged assets should match the remote inventory count.
+func mergeIncremental(stored, head *inventory) (*inventory, bool) {
+	if stored == nil {
+		return nil, false
+	}
+	base := slices.IndexFunc(stored.Assets, func(a asset) bool { return a.AssetID == head.BaseAssetID })
+	if base < 0 || len(head.Assets)+len(stored.Assets)-base != head.TotalInventoryCount {
+		return nil, false
+	}
+	if !slices.Equal(assetIDs(stored.Assets[base:]), head.TailAssetIDs) {
+		return nil, false
+	}
+
+	merged := merge(
+		&inventory{Assets: head.Assets, Descriptions: head.Descriptions},
+		&inventory{Assets: stored.Assets[base:], Descriptions: stored.Descriptions},
+	)
+	merged.TotalInventoryCount = head.TotalInventoryCount
+
+	// drop descriptions of assets that left the inventory.
+	used := map[string]bool{}
+	for _, a := range merged.Assets {
+		used[a.ClassID+"-"+a.InstanceID] = true
+	}
+	merged.Descriptions = slices.DeleteFunc(merged.Descriptions, func(d description) bool {
+		return !used[d.ClassID+"-"+d.InstanceID]
+	})
+	return merged, true
+}
+
+// verifyWebhook checks payload signature against active keys and rejects
+// stale and replayed payloads.
+func (s *Service) verifyWebhook(ctx context.Context, steamID string, sig WebhookSignature, body []byte) error {
+	key, ok := s.webhookKeys[sig.KeyID]
+	if !ok {
+		return errWebhookSignature
+	}
+	ts, err := strconv.ParseInt(sig.Timestamp, 10, 64)
+	if err != nil {
+		return errWebhookSignature
+	}
+	want := SignWebhook(key, steamID, ts, body)
+	if !hmac.Equal([]byte(sig.Signature), []byte(want)) {
+		return errWebhookSignature
+	}
+
+	age := time.Since(time.Unix(ts, 0))
+	if age > s.config.WebhookMaxAge || age < -s.config.WebhookMaxAge {
+		return fmt.Errorf("%w: stale payload", errWebhookSignature)
+	}
+	// signature is kept until its timestamp is stale to reject replays.
+	fresh, err := s.cooldown.ClaimWebhookSignature(ctx, sig.Signature, s.config.WebhookMaxAge*2)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return fmt.Errorf("%w: replayed payload", errWebhookSignature)
+	}
+	return nil
+}
+
 func (s *Service) InventoryAsset(ctx context.Context, steamID string) ([]steam.Asset, error) {
 	raw, err := s.crawlWait(ctx, steamID)
 	if err != nil {
@@ -116,11 +276,46 @@ func (s *Service) InventoryAsset(ctx context.Context, steamID string) ([]steam.A
 	return compat.ToAssets(), nil
 }
 
+// ParseInventory returns a compact format from a captured crawler inventory data.
+func ParseInventory(r io.Reader) ([]steam.Asset, error) {
+	var inv inventory
+	if err := fastjson.NewDecoder(r).Decode(&inv); err != nil {
+		return nil, err
+	}
+
+	compat := inv.compat()
+	return compat.ToAssets(), nil
+}
+
 func (s *Service) InventoryAssetWithProvider(ctx context.Context, steamID string) (string, []steam.Asset, error) {
 	res, err := s.InventoryAsset(ctx, steamID)
 	return s.id, res, err
 }
 
+// Name returns the inventory provider id.
+func (s *Service) Name() string { return s.id }
+
+// Capabilities returns stored inventory that is fresh within the inventory
+// hash ttl and confirmed by precheck after.
+func (s *Service) Capabilities() dotagiftx.ProviderCapabilities {
+	return dotagiftx.ProviderCapabilities{MaxAge: s.inventoryHashTTL, Cached: true, Private: true}
+}
+
+// Health returns an error when the whole crawler fleet is quarantined.
+func (s *Service) Health(ctx context.Context) error {
+	fleet, err := s.crawlers.fleet(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, c := range fleet {
+		if !c.Quarantined(now) {
+			return nil
+		}
+	}
+	return errors.New("all crawlers are quarantined")
+}
+
 func (s *Service) Invalidate(ctx context.Context, steamID string) error {
 	if err := s.cooldown.SetInventoryHash(ctx, steamID, "", s.inventoryHashTTL); err != nil {
 		return fmt.Errorf("invalidate inventory hash: %s", err)
@@ -128,24 +323,19 @@ func (s *Service) Invalidate(ctx context.Context, steamID string) error {
 	return nil
 }
 
-func (s *Service) CleanLocalCache(ctx context.Context, maxAge time.Duration) error {
-	files, err := os.ReadDir(s.config.Path)
+// CleanLocalCache removes expired raw inventories.
+func (s *Service) CleanLocalCache(ctx context.Context) error {
+	blobs, err := s.blobs.List(ctx)
 	if err != nil {
-		return fmt.Errorf("read dir: %s", err)
+		return fmt.Errorf("list blobs: %s", err)
 	}
 
-	for _, file := range files {
-		info, err := file.Info()
-		if err != nil {
-			return fmt.Errorf("%s file info: %s", file, err)
-		}
-
-		if time.Since(info.ModTime()) < maxAge {
+	now := time.Now()
+	for _, b := range blobs {
+		if now.Before(b.ExpiresAt) {
 			continue
 		}
-
-		f := filepath.Join(s.config.Path, file.Name())
-		if err = os.Remove(f); err != nil {
+		if err = s.blobs.Delete(ctx, b.Key); err != nil {
 			return err
 		}
 	}
@@ -155,7 +345,10 @@ func (s *Service) CleanLocalCache(ctx context.Context, maxAge time.Duration) err
 
 // crawlWait retrieves the inventory local file when available and fetch it when missing.
 func (s *Service) crawlWait(ctx context.Context, steamID string) (*inventory, error) {
-	crawlerURL := s.config.Addrs[s.electedCrawlerID]
+	crawlerURL, err := s.crawlers.crawler(ctx)
+	if err != nil {
+		return nil, err
+	}
 	crawlerID := extractCrawlerID(crawlerURL)
 	logger := s.logger.With("steam_id", steamID, "crawler_id", crawlerID)
 
@@ -186,7 +379,7 @@ func (s *Service) crawlWait(ctx context.Context, steamID string) (*inventory, er
 
 		// pre-check before fetching
 		logger.DebugContext(ctx, "check remote inventory changes", "hash", hash)
-		changed, err := s.remoteInventoryChanged(ctx, steamID)
+		changed, err := s.remoteInventoryChanged(ctx, crawlerURL, steamID)
 		if err != nil {
 			logger.Error("precheck remote inventory", "err", err)
 			return nil, err
@@ -194,8 +387,7 @@ func (s *Service) crawlWait(ctx context.Context, steamID string) (*inventory, er
 		if !changed {
 			logger.DebugContext(ctx, "remote inventory did not changed, falling back to local")
 			// refresh inventory file age
-			n := time.Now()
-			if err = os.Chtimes(s.filePath(steamID), n, n); err != nil {
+			if err = s.blobs.Touch(ctx, blobKey(steamID), s.config.Blob.TTL); err != nil {
 				return nil, err
 			}
 			return localFile, nil
@@ -205,25 +397,33 @@ func (s *Service) crawlWait(ctx context.Context, steamID string) (*inventory, er
 	}
 
 	// precheck to filter out private or failing remote inventory.
-	if _, err = s.sendCrawlRequest(ctx, crawlerURL, steamID, true); err != nil {
+	if _, err = s.sendCrawlRequest(ctx, crawlerURL, CrawlRequest{SteamID: steamID, Precheck: true}); err != nil {
 		return nil, err
 	}
 
-	// don't retry if it's not on waiting state.
+	// subscribe before crawling so the saved signal is not missed when the
+	// webhook lands on any instance before the crawl request returns.
+	saved, unsubscribe, err := s.cooldown.SubscribeInventorySaved(ctx, steamID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := unsubscribe(); err != nil {
+			logger.Error("unsubscribe inventory saved", "err", err)
+		}
+	}()
+
+	// don't wait if it's not on waiting state.
 	logger.DebugContext(ctx, "local file not found, crawling...")
-	err = s.crawlRemoteInventory(ctx, steamID)
+	err = s.crawlRemoteInventory(ctx, crawlerURL, steamID, localFile)
 	if err != nil && !errors.Is(err, errFileWaiting) {
 		return nil, err
 	}
-	// retry if it's on waiting state.
+	// other crawl is in progress and wait for its webhook.
 	if errors.Is(err, errFileWaiting) {
-		for i := range maxWaitRetry {
-			wait := time.Duration(i*i) * time.Second
-			time.Sleep(wait)
-			logger.DebugContext(ctx, "reading local data", "attempt", i+1, "waiting", wait)
-			if _, err = s.localInventoryFile(ctx, steamID); err != nil && !errors.Is(err, errFileNotFound) {
-				return nil, err
-			}
+		logger.DebugContext(ctx, "waiting for inventory saved signal")
+		if err = waitSaved(ctx, saved); err != nil {
+			return nil, err
 		}
 	}
 	// check raw inventory again, but what error you have you need to go.
@@ -240,8 +440,29 @@ func (s *Service) crawlWait(ctx context.Context, steamID string) (*inventory, er
 	return localFile, nil
 }
 
-func (s *Service) crawlRemoteInventory(ctx context.Context, steamID string) error {
-	crawlerURL := s.config.Addrs[s.electedCrawlerID]
+// waitSaved blocks until the inventory saved signal or the context deadline,
+// without deadline it gives up after maxCrawlWait and falls back to stored
+// inventory.
+func waitSaved(ctx context.Context, saved <-chan struct{}) error {
+	var timeout <-chan time.Time
+	if _, ok := ctx.Deadline(); !ok {
+		t := time.NewTimer(maxCrawlWait)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	select {
+	case <-saved:
+	case <-timeout:
+	case <-ctx.Done():
+		return fmt.Errorf("wait inventory saved: %w", ctx.Err())
+	}
+	return nil
+}
+
+// crawlRemoteInventory requests a crawl that is incremental from the stored
+// inventory when available.
+func (s *Service) crawlRemoteInventory(ctx context.Context, crawlerURL, steamID string, stored *inventory) error {
 	crawlerID := extractCrawlerID(crawlerURL)
 	logger := s.logger.With("steam_id", steamID, "crawler_id", crawlerID)
 
@@ -267,12 +488,18 @@ func (s *Service) crawlRemoteInventory(ctx context.Context, steamID string) erro
 		return err
 	}
 
-	summary, err := s.sendCrawlRequest(ctx, crawlerURL, steamID, false)
+	req := CrawlRequest{SteamID: steamID}
+	if stored != nil && len(stored.Assets) != 0 {
+		req.KnownAssetID = stored.Assets[0].AssetID
+		req.KnownCount = len(stored.Assets)
+	}
+	summary, err := s.sendCrawlRequest(ctx, crawlerURL, req)
 	if err != nil {
 		return err
 	}
 	logger.DebugContext(ctx,
 		"fetch remote inventory",
+		"incremental", summary.Incremental,
 		"count", summary.InventoryCount,
 		"parts", summary.Parts,
 		"query_limit", summary.QueryLimit,
@@ -282,12 +509,11 @@ func (s *Service) crawlRemoteInventory(ctx context.Context, steamID string) erro
 	return nil
 }
 
-func (s *Service) remoteInventoryChanged(ctx context.Context, steamID string) (bool, error) {
-	crawlerURL := s.config.Addrs[s.electedCrawlerID]
+func (s *Service) remoteInventoryChanged(ctx context.Context, crawlerURL, steamID string) (bool, error) {
 	crawlerID := extractCrawlerID(crawlerURL)
 	logger := s.logger.With("steam_id", steamID, "crawler_id", crawlerID)
 
-	result, err := s.sendCrawlRequest(ctx, crawlerURL, steamID, true)
+	result, err := s.sendCrawlRequest(ctx, crawlerURL, CrawlRequest{SteamID: steamID, Precheck: true})
 	if err != nil {
 		return false, err
 	}
@@ -306,12 +532,16 @@ func (s *Service) remoteInventoryChanged(ctx context.Context, steamID string) (b
 }
 
 func (s *Service) localInventoryFile(ctx context.Context, steamID string) (*inventory, error) {
-	file, err := os.ReadFile(s.filePath(steamID))
+	b, err := s.blobs.Get(ctx, blobKey(steamID))
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, errBlobNotFound) {
 			return nil, errFileNotFound
 		}
-		return nil, fmt.Errorf("open file: %s", err)
+		return nil, fmt.Errorf("get blob: %s", err)
+	}
+	file, err := decompress(b)
+	if err != nil {
+		return nil, fmt.Errorf("decompress: %s", err)
 	}
 
 	var inv inventory
@@ -321,8 +551,13 @@ func (s *Service) localInventoryFile(ctx context.Context, steamID string) (*inve
 	return &inv, nil
 }
 
+// Crawlers returns the crawler fleet health stats.
+func (s *Service) Crawlers(ctx context.Context) ([]CrawlerStats, error) {
+	return s.crawlers.fleet(ctx)
+}
+
 func (s *Service) electNewCrawler(ctx context.Context) string {
-	crawler := extractCrawlerID(s.config.Addrs[s.electedCrawlerID])
+	crawler := extractCrawlerID(s.crawlers.current())
 	cd, err := s.cooldown.CrawlerCooldown(ctx, crawler)
 	if err != nil {
 		s.logger.ErrorContext(ctx, "crawler cooldown", "crawler", crawler, "err", err)
@@ -333,24 +568,27 @@ func (s *Service) electNewCrawler(ctx context.Context) string {
 		}
 	}
 
-	s.electedCrawlerID++
-	if s.electedCrawlerID >= len(s.config.Addrs) {
-		s.electedCrawlerID = 0
+	elected, err := s.crawlers.elect(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "crawler election", "err", err)
+		return crawler
 	}
-	return extractCrawlerID(s.config.Addrs[s.electedCrawlerID])
+	return extractCrawlerID(elected)
 }
 
 func (s *Service) sendCrawlRequest(
 	ctx context.Context,
 	crawlerURL string,
-	steamID string,
-	precheck bool,
+	r CrawlRequest,
 ) (*CrawlSummary, error,
 ) {
-	url := fmt.Sprintf("%s?steam_id=%s", crawlerURL, steamID)
-	if precheck {
+	url := fmt.Sprintf("%s?steam_id=%s", crawlerURL, r.SteamID)
+	if r.Precheck {
 		url = fmt.Sprintf("%s&precheck", url)
 	}
+	if r.KnownAssetID != "" {
+		url = fmt.Sprintf("%s&known_asset_id=%s&known_count=%d", url, r.KnownAssetID, r.KnownCount)
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
 	if err != nil {
@@ -360,6 +598,11 @@ func (s *Service) sendCrawlRequest(
 
 	var summary CrawlSummary
 	statusCode, err := sendRequest(req, &summary)
+	// private inventory is a valid crawler answer.
+	failed := err != nil && statusCode != http.StatusForbidden
+	if rerr := s.crawlers.record(ctx, crawlerURL, &summary, statusCode, failed); rerr != nil {
+		s.logger.ErrorContext(ctx, "record crawler stats", "crawler", extractCrawlerID(crawlerURL), "err", rerr)
+	}
 	if err != nil {
 		if statusCode == http.StatusForbidden {
 			return nil, steam.ErrInventoryPrivate
@@ -385,8 +628,8 @@ func (s *Service) setRequestHeaders() http.Header {
 	return h
 }
 
-func (s *Service) filePath(steamID string) string {
-	return filepath.Join(s.config.Path, fmt.Sprintf("%s.json", steamID))
+func blobKey(steamID string) string {
+	return fmt.Sprintf("%s.json.gz", steamID)
 }
 
 func (i *inventory) compat() steam.AllInventory {
@@ -437,6 +680,21 @@ type cooldown interface {
 
 	InventoryHash(ctx context.Context, steamID string) (hash string, error error)
 	SetInventoryHash(ctx context.Context, steamID, hash string, ttl time.Duration) error
+
+	// ClaimWebhookSignature returns false when the signature was already used.
+	ClaimWebhookSignature(ctx context.Context, signature string, ttl time.Duration) (bool, error)
+
+	// PublishInventorySaved signals waiting crawls on all instances that the
+	// inventory of steam id was saved.
+	PublishInventorySaved(ctx context.Context, steamID string) error
+	// SubscribeInventorySaved returns a channel that receives the saved
+	// signal of steam id until unsubscribed.
+	SubscribeInventorySaved(ctx context.Context, steamID string) (saved <-chan struct{}, unsubscribe func() error, err error)
+
+	// CrawlerStats and UpdateCrawlerStats keeps encoded crawler health
+	// stats, update applies fn on the current stats atomically.
+	CrawlerStats(ctx context.Context, crawlID string) ([]byte, error)
+	UpdateCrawlerStats(ctx context.Context, crawlID string, fn func(stats []byte) ([]byte, error)) error
 }
 
 func extractCrawlerID(addr string) string {
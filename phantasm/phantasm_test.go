@@ -0,0 +1,64 @@
+package phantasm
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_mergeIncremental(t *testing.T) {
+	inv := func(base string, total int, ids ...string) *inventory {
+		i := &inventory{BaseAssetID: base, TotalInventoryCount: total}
+		for _, id := range ids {
+			i.Assets = append(i.Assets, asset{AssetID: id, ClassID: "c" + id})
+			i.Descriptions = append(i.Descriptions, description{ClassID: "c" + id})
+		}
+		return i
+	}
+	tail := func(i *inventory, ids ...string) *inventory {
+		i.TailAssetIDs = ids
+		return i
+	}
+	stored := inv("", 4, "4", "3", "2", "1")
+
+	tests := []struct {
+		name   string
+		stored *inventory
+		head   *inventory
+		want   []string
+		wantOK bool
+	}{
+		{"new assets", stored, tail(inv("4", 6, "6", "5"), "4", "3", "2", "1"), []string{"6", "5", "4", "3", "2", "1"}, true},
+		{"newest assets removed", stored, tail(inv("3", 4, "5"), "3", "2", "1"), []string{"5", "3", "2", "1"}, true},
+		{"count mismatch", stored, tail(inv("4", 5, "6", "5"), "4", "3", "2", "1"), nil, false},
+		{"one out one in", stored, tail(inv("4", 5, "5"), "4", "3", "2", "0"), nil, false},
+		{"unverified tail", stored, inv("4", 5, "5"), nil, false},
+		{"unknown base", stored, inv("9", 5, "5"), nil, false},
+		{"no stored", nil, inv("4", 5, "5"), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := mergeIncremental(tt.stored, tt.head)
+			if ok != tt.wantOK {
+				t.Fatalf("mergeIncremental() ok = %t, want %t", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			var ids, descs []string
+			for _, a := range got.Assets {
+				ids = append(ids, a.AssetID)
+			}
+			for _, d := range got.Descriptions {
+				descs = append(descs, d.ClassID[1:])
+			}
+			slices.Sort(descs)
+			want := slices.Sorted(slices.Values(tt.want))
+			if !slices.Equal(ids, tt.want) || !slices.Equal(descs, want) {
+				t.Errorf("mergeIncremental() assets = %v descriptions = %v, want %v", ids, descs, tt.want)
+			}
+			if got.TotalInventoryCount != tt.head.TotalInventoryCount || got.BaseAssetID != "" {
+				t.Errorf("mergeIncremental() total = %d base = %q", got.TotalInventoryCount, got.BaseAssetID)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package phantasm
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	defaultQuarantine = time.Minute * 10
+
+	// statsSmoothing is the weight of the latest request on success rate and
+	// elapsed time moving averages.
+	statsSmoothing = 0.2
+
+	// quarantine thresholds of recent rate limits and success rate after
+	// minimum requests.
+	quarantineRateLimits  = 3
+	quarantineSuccessRate = 0.3
+	quarantineMinRequests = 5
+	rateLimitWindow       = time.Minute * 10
+	probationSuccessRate  = 0.5
+)
+
+// CrawlerStats represents the health of a crawler in the fleet.
+type CrawlerStats struct {
+	ID               string     `json:"id"`
+	Addr             string     `json:"addr"`
+	Requests         int        `json:"requests"`
+	Failures         int        `json:"failures"`
+	SuccessRate      float64    `json:"success_rate"`
+	AvgElapsedSec    float64    `json:"avg_elapsed_sec"`
+	RecentRateLimits int        `json:"recent_rate_limits"`
+	RateLimitedAt    *time.Time `json:"rate_limited_at"`
+	LastSeenAt       *time.Time `json:"last_seen_at"`
+	QuarantinedUntil *time.Time `json:"quarantined_until"`
+	Elected          bool       `json:"elected"`
+}
+
+// Quarantined reports whether the crawler is excluded from election.
+func (c CrawlerStats) Quarantined(now time.Time) bool {
+	return c.QuarantinedUntil != nil && now.Before(*c.QuarantinedUntil)
+}
+
+// weight favors crawlers with high success rate, fast crawls and less
+// recent rate limits.
+func (c CrawlerStats) weight() float64 {
+	return c.SuccessRate / (1 + c.AvgElapsedSec/10) / float64(1+c.RecentRateLimits)
+}
+
+// registry keeps crawler stats on the shared store so all services elect
+// from the same fleet state.
+type registry struct {
+	store      cooldown
+	addrs      []string
+	quarantine time.Duration
+
+	mu      sync.Mutex
+	elected int
+}
+
+func newRegistry(store cooldown, addrs []string, quarantine time.Duration) *registry {
+	return &registry{store: store, addrs: addrs, quarantine: quarantine}
+}
+
+// stats returns the crawler stats, new crawler starts healthy.
+func (r *registry) stats(ctx context.Context, addr string) (*CrawlerStats, error) {
+	id := extractCrawlerID(addr)
+	b, err := r.store.CrawlerStats(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCrawlerStats(id, addr, b)
+}
+
+func decodeCrawlerStats(id, addr string, b []byte) (*CrawlerStats, error) {
+	stats := &CrawlerStats{ID: id, Addr: addr, SuccessRate: 1}
+	if len(b) == 0 {
+		return stats, nil
+	}
+	if err := fastjson.Unmarshal(b, stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// record updates crawler stats from the crawl request result and quarantines
+// the crawler when it's unhealthy, stats are updated atomically since all
+// services records on the same fleet state.
+func (r *registry) record(ctx context.Context, addr string, summary *CrawlSummary, statusCode int, failed bool) error {
+	id := extractCrawlerID(addr)
+	return r.store.UpdateCrawlerStats(ctx, id, func(b []byte) ([]byte, error) {
+		stats, err := decodeCrawlerStats(id, addr, b)
+		if err != nil {
+			return nil, err
+		}
+		r.apply(stats, summary, statusCode, failed)
+		return fastjson.Marshal(stats)
+	})
+}
+
+func (r *registry) apply(stats *CrawlerStats, summary *CrawlSummary, statusCode int, failed bool) {
+	now := time.Now()
+	stats.Requests++
+	if stats.RateLimitedAt != nil && now.Sub(*stats.RateLimitedAt) > rateLimitWindow {
+		stats.RecentRateLimits = 0
+	}
+	ok := 0.0
+	switch {
+	case !failed:
+		ok = 1
+		stats.LastSeenAt = &now
+		if summary != nil && !summary.Precheck {
+			stats.AvgElapsedSec = movingAvg(stats.AvgElapsedSec, summary.ElapsedSec, stats.Requests)
+		}
+	case statusCode == http.StatusTooManyRequests:
+		stats.Failures++
+		stats.RecentRateLimits++
+		stats.RateLimitedAt = &now
+	default:
+		stats.Failures++
+	}
+	stats.SuccessRate = movingAvg(stats.SuccessRate, ok, stats.Requests)
+
+	if stats.RecentRateLimits >= quarantineRateLimits ||
+		(stats.Requests >= quarantineMinRequests && stats.SuccessRate < quarantineSuccessRate) {
+		until := now.Add(r.quarantine)
+		stats.QuarantinedUntil = &until
+		// Crawler is on probation after quarantine.
+		stats.RecentRateLimits = 0
+		stats.SuccessRate = probationSuccessRate
+	}
+}
+
+func movingAvg(avg, v float64, n int) float64 {
+	if n <= 1 {
+		return v
+	}
+	return avg*(1-statsSmoothing) + v*statsSmoothing
+}
+
+// fleet returns stats of all crawlers.
+func (r *registry) fleet(ctx context.Context) ([]CrawlerStats, error) {
+	elected := r.current()
+	var fleet []CrawlerStats
+	for _, addr := range r.addrs {
+		stats, err := r.stats(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+		stats.Elected = addr == elected
+		fleet = append(fleet, *stats)
+	}
+	return fleet, nil
+}
+
+func (r *registry) current() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.addrs[r.elected]
+}
+
+// crawler returns the elected crawler address and elects a new one when
+// it's quarantined.
+func (r *registry) crawler(ctx context.Context) (string, error) {
+	addr := r.current()
+	stats, err := r.stats(ctx, addr)
+	if err != nil {
+		return "", err
+	}
+	if !stats.Quarantined(time.Now()) {
+		return addr, nil
+	}
+	return r.elect(ctx)
+}
+
+// elect picks a crawler other than the current by weighted random of their
+// health, quarantined crawlers are skipped unless the whole fleet is, then
+// the one released the earliest is elected.
+func (r *registry) elect(ctx context.Context) (string, error) {
+	fleet, err := r.fleet(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	var total float64
+	var candidates []int
+	for i, c := range fleet {
+		if c.Quarantined(now) || (c.Elected && len(fleet) > 1) {
+			continue
+		}
+		candidates = append(candidates, i)
+		total += c.weight()
+	}
+
+	var pick int
+	if len(candidates) == 0 {
+		// Current is the only healthy crawler or the whole fleet is quarantined.
+		pick = slices.IndexFunc(fleet, func(c CrawlerStats) bool { return !c.Quarantined(now) })
+		if pick < 0 {
+			pick = 0
+			for i, c := range fleet {
+				if c.QuarantinedUntil.Before(*fleet[pick].QuarantinedUntil) {
+					pick = i
+				}
+			}
+		}
+	} else {
+		pick = candidates[0]
+		n := rand.Float64() * total
+		for _, i := range candidates {
+			if n -= fleet[i].weight(); n < 0 {
+				pick = i
+				break
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.elected = pick
+	r.mu.Unlock()
+	return fleet[pick].Addr, nil
+}
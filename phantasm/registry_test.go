@@ -0,0 +1,125 @@
+package phantasm
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testStore struct {
+	cooldown
+	mu    sync.Mutex
+	stats map[string][]byte
+}
+
+func (s *testStore) CrawlerStats(_ context.Context, crawlID string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stats[crawlID], nil
+}
+
+func (s *testStore) UpdateCrawlerStats(_ context.Context, crawlID string, fn func([]byte) ([]byte, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := fn(s.stats[crawlID])
+	if err != nil {
+		return err
+	}
+	s.stats[crawlID] = b
+	return nil
+}
+
+func TestRegistry_quarantine(t *testing.T) {
+	ctx := context.Background()
+	addrs := []string{"http://a/crawler/one", "http://b/crawler/two"}
+	reg := newRegistry(&testStore{stats: map[string][]byte{}}, addrs, time.Hour)
+
+	for range quarantineRateLimits {
+		if err := reg.record(ctx, addrs[0], nil, http.StatusTooManyRequests, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := reg.record(ctx, addrs[1], &CrawlSummary{ElapsedSec: 4}, http.StatusOK, false); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := reg.crawler(ctx)
+	if err != nil || got != addrs[1] {
+		t.Fatalf("crawler() = %s, %v, want %s elected over quarantined", got, err, addrs[1])
+	}
+
+	fleet, err := reg.fleet(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fleet[0].Quarantined(time.Now()) || fleet[0].Failures != quarantineRateLimits {
+		t.Errorf("fleet() first crawler = %+v, want quarantined", fleet[0])
+	}
+	if !fleet[1].Elected || fleet[1].SuccessRate != 1 || fleet[1].AvgElapsedSec != 4 || fleet[1].LastSeenAt == nil {
+		t.Errorf("fleet() second crawler = %+v, want elected and healthy", fleet[1])
+	}
+
+	// Whole fleet quarantined elects the earliest released.
+	for range quarantineRateLimits {
+		reg.record(ctx, addrs[1], nil, http.StatusTooManyRequests, true)
+	}
+	if got, _ = reg.elect(ctx); got != addrs[0] {
+		t.Errorf("elect() = %s, want %s released earliest", got, addrs[0])
+	}
+}
+
+func TestRegistry_electWeighted(t *testing.T) {
+	ctx := context.Background()
+	addrs := []string{"http://a/crawler/one", "http://b/crawler/two", "http://c/crawler/three"}
+	reg := newRegistry(&testStore{stats: map[string][]byte{}}, addrs, time.Hour)
+	for range quarantineMinRequests - 1 {
+		reg.record(ctx, addrs[1], nil, http.StatusBadGateway, true)
+	}
+	reg.record(ctx, addrs[2], &CrawlSummary{ElapsedSec: 1}, http.StatusOK, false)
+
+	picks := map[string]int{}
+	for range 200 {
+		reg.mu.Lock()
+		reg.elected = 0
+		reg.mu.Unlock()
+		got, err := reg.elect(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		picks[got]++
+	}
+	if picks[addrs[0]] != 0 {
+		t.Errorf("elect() picked current crawler %d times", picks[addrs[0]])
+	}
+	if picks[addrs[2]] <= picks[addrs[1]] {
+		t.Errorf("elect() picks = %v, want healthy crawler favored", picks)
+	}
+}
+
+func TestRegistry_recordConcurrent(t *testing.T) {
+	ctx := context.Background()
+	addr := "http://a/crawler/one"
+	reg := newRegistry(&testStore{stats: map[string][]byte{}}, []string{addr}, time.Hour)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			if err := reg.record(ctx, addr, &CrawlSummary{ElapsedSec: 1}, http.StatusOK, false); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	stats, err := reg.stats(ctx, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Requests != 20 {
+		t.Errorf("record() requests = %d, want 20 without lost updates", stats.Requests)
+	}
+}
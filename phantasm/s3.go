@@ -0,0 +1,272 @@
+package phantasm
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	s3ExpiresHeader = "X-Amz-Meta-Expires-At"
+	s3TimeFormat    = "20060102T150405Z"
+	s3DateFormat    = "20060102"
+)
+
+// NewS3Blob returns a blob store on S3-compatible storage like AWS S3 and
+// MinIO, objects are addressed by path style and expiry is kept on object
+// metadata.
+func NewS3Blob(c BlobConfig) (BlobStore, error) {
+	if c.Endpoint == "" || c.Bucket == "" || c.AccessKey == "" || c.SecretKey == "" {
+		return nil, errors.New("s3 endpoint, bucket and keys required")
+	}
+	c = c.setDefault()
+	return &s3Blob{
+		config: c,
+		client: &http.Client{Timeout: time.Minute},
+		now:    time.Now,
+	}, nil
+}
+
+type s3Blob struct {
+	config BlobConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+func (b *s3Blob) Put(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	h := http.Header{}
+	h.Set("Content-Type", "application/gzip")
+	h.Set(s3ExpiresHeader, b.expiry(ttl))
+	res, err := b.do(ctx, http.MethodPut, key, nil, h, data)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (b *s3Blob) Get(ctx context.Context, key string) ([]byte, error) {
+	res, err := b.do(ctx, http.MethodGet, key, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if b.expired(res.Header) {
+		return nil, errBlobNotFound
+	}
+	return io.ReadAll(res.Body)
+}
+
+// Touch copies the object on itself to replace its expiry metadata.
+func (b *s3Blob) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	h := http.Header{}
+	h.Set("Content-Type", "application/gzip")
+	h.Set(s3ExpiresHeader, b.expiry(ttl))
+	h.Set("X-Amz-Copy-Source", "/"+b.config.Bucket+"/"+s3Escape(key, false))
+	h.Set("X-Amz-Metadata-Directive", "REPLACE")
+	res, err := b.do(ctx, http.MethodPut, key, nil, h, nil)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (b *s3Blob) Delete(ctx context.Context, key string) error {
+	res, err := b.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if errors.Is(err, errBlobNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// List pages through bucket objects and reads each object expiry, blob
+// without expiry metadata is treated as expired.
+func (b *s3Blob) List(ctx context.Context) ([]BlobInfo, error) {
+	var blobs []BlobInfo
+	query := url.Values{"list-type": {"2"}}
+	for {
+		res, err := b.do(ctx, http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Contents []struct {
+				Key string
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		err = xml.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decode list: %s", err)
+		}
+
+		for _, obj := range page.Contents {
+			res, err = b.do(ctx, http.MethodHead, obj.Key, nil, nil, nil)
+			if errors.Is(err, errBlobNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			res.Body.Close()
+			exp, _ := time.Parse(time.RFC3339, res.Header.Get(s3ExpiresHeader))
+			blobs = append(blobs, BlobInfo{obj.Key, exp})
+		}
+
+		if !page.IsTruncated {
+			return blobs, nil
+		}
+		query.Set("continuation-token", page.NextContinuationToken)
+	}
+}
+
+func (b *s3Blob) expiry(ttl time.Duration) string {
+	return b.now().Add(ttl).UTC().Format(time.RFC3339)
+}
+
+func (b *s3Blob) expired(h http.Header) bool {
+	exp, err := time.Parse(time.RFC3339, h.Get(s3ExpiresHeader))
+	return err != nil || b.now().After(exp)
+}
+
+// do sends a signed request on the object key or the bucket when key is
+// empty, not found response returns errBlobNotFound.
+func (b *s3Blob) do(ctx context.Context, method, key string, query url.Values, h http.Header, body []byte) (*http.Response, error) {
+	if key != "" && !validBlobKey(key) {
+		return nil, fmt.Errorf("invalid blob key %q", key)
+	}
+
+	path := "/" + b.config.Bucket
+	if key != "" {
+		path += "/" + key
+	}
+	u := strings.TrimRight(b.config.Endpoint, "/") + s3Escape(path, false)
+	if len(query) != 0 {
+		u += "?" + s3Query(query)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range h {
+		req.Header[k] = v
+	}
+	req.ContentLength = int64(len(body))
+	b.sign(req, body)
+
+	res, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 300 {
+		return res, nil
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, errBlobNotFound
+	}
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return nil, fmt.Errorf("s3 %s %s: %s %s", method, path, res.Status, msg)
+}
+
+// sign adds AWS signature version 4 authorization to the request.
+func (b *s3Blob) sign(req *http.Request, body []byte) {
+	now := b.now().UTC()
+	amzDate := now.Format(s3TimeFormat)
+	date := now.Format(s3DateFormat)
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	var names []string
+	for k := range req.Header {
+		k = strings.ToLower(k)
+		if k == "host" || k == "content-type" || strings.HasPrefix(k, "x-amz-") {
+			names = append(names, k)
+		}
+	}
+	slices.Sort(names)
+	var headers strings.Builder
+	for _, k := range names {
+		headers.WriteString(k + ":" + strings.TrimSpace(req.Header.Get(k)) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		s3Escape(req.URL.Path, false),
+		req.URL.RawQuery,
+		headers.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonical))
+	scope := date + "/" + b.config.Region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+b.config.SecretKey), date)
+	key = hmacSHA256(key, b.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		b.config.AccessKey, scope, signedHeaders, signature))
+	req.Header.Del("Host")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Query returns canonical query string sorted by key.
+func s3Query(v url.Values) string {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, val := range v[k] {
+			parts = append(parts, s3Escape(k, true)+"="+s3Escape(val, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape encodes everything except unreserved characters, slashes are
+// kept on paths.
+func s3Escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
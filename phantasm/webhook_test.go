@@ -0,0 +1,111 @@
+package phantasm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testSignatures struct {
+	cooldown
+	used      map[string]bool
+	published []string
+}
+
+func (s *testSignatures) PublishInventorySaved(_ context.Context, steamID string) error {
+	s.published = append(s.published, steamID)
+	return nil
+}
+
+func (s *testSignatures) ClaimWebhookSignature(_ context.Context, signature string, _ time.Duration) (bool, error) {
+	if s.used[signature] {
+		return false, nil
+	}
+	s.used[signature] = true
+	return true, nil
+}
+
+func TestService_SaveInventory(t *testing.T) {
+	dir := t.TempDir()
+	signatures := &testSignatures{used: map[string]bool{}}
+	svc, err := NewService(Config{
+		Path: dir,
+		Keys: []string{"new:rotated-secret", "old:reality-rift"},
+	}, signatures, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const steamID = "76561198088587178"
+	body := `{"assets":[],"success":1}`
+	sign := func(keyID, secret string, at time.Time, steamID, body string) WebhookSignature {
+		ts := at.Unix()
+		return WebhookSignature{keyID, strconv.FormatInt(ts, 10), SignWebhook([]byte(secret), steamID, ts, []byte(body))}
+	}
+	now := time.Now()
+	replayed := sign("new", "rotated-secret", now, steamID, body)
+
+	tests := []struct {
+		name    string
+		sig     WebhookSignature
+		body    string
+		wantErr bool
+	}{
+		{"current key", replayed, body, false},
+		{"previous key", sign("old", "reality-rift", now, steamID, body), body, false},
+		{"replayed", replayed, body, true},
+		{"unknown key", sign("gone", "reality-rift", now, steamID, body), body, true},
+		{"wrong secret", sign("new", "reality-rift", now, steamID, body), body, true},
+		{"tampered body", sign("new", "rotated-secret", now.Add(time.Second), steamID, body), `{"assets":[{}]}`, true},
+		{"other steam id", sign("new", "rotated-secret", now.Add(time.Second*2), "76561198000000000", body), body, true},
+		{"stale", sign("new", "rotated-secret", now.Add(-time.Hour), steamID, body), body, true},
+		{"future", sign("new", "rotated-secret", now.Add(time.Hour), steamID, body), body, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := svc.blobs.Delete(ctx, blobKey(steamID)); err != nil {
+				t.Fatal(err)
+			}
+			err := svc.SaveInventory(ctx, steamID, tt.sig, io.NopCloser(strings.NewReader(tt.body)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SaveInventory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, errWebhookSignature) {
+				t.Errorf("SaveInventory() error = %v, want %v", err, errWebhookSignature)
+			}
+			_, getErr := svc.localInventoryFile(ctx, steamID)
+			if saved := getErr == nil; saved == tt.wantErr {
+				t.Errorf("SaveInventory() saved = %t, want %t", saved, !tt.wantErr)
+			}
+			published := len(signatures.published) > 0
+			if published == tt.wantErr {
+				t.Errorf("SaveInventory() published = %t, want %t", published, !tt.wantErr)
+			}
+			signatures.published = nil
+		})
+	}
+}
+
+func Test_waitSaved(t *testing.T) {
+	saved := make(chan struct{}, 1)
+	saved <- struct{}{}
+	if err := waitSaved(context.Background(), saved); err != nil {
+		t.Fatalf("waitSaved() signaled error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	start := time.Now()
+	if err := waitSaved(ctx, saved); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("waitSaved() deadline error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("waitSaved() waited %s past the deadline", elapsed)
+	}
+}
@@ -0,0 +1,101 @@
+package dotagiftx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	// InventoryProvider represents a steam inventory source that can be
+	// registered for asset verification.
+	InventoryProvider interface {
+		// Name returns the provider id that shows on verification results.
+		Name() string
+
+		// Capabilities returns what the provider supports and guarantees.
+		Capabilities() ProviderCapabilities
+
+		// InventoryAsset returns a compact format of steam id inventory.
+		InventoryAsset(ctx context.Context, steamID string) ([]SteamAsset, error)
+
+		// Invalidate drops cached inventory of steam id, does nothing on
+		// providers without cache.
+		Invalidate(ctx context.Context, steamID string) error
+
+		// Health returns an error when provider is unable to serve requests.
+		Health(ctx context.Context) error
+	}
+
+	// ProviderCapabilities represents inventory provider guarantees.
+	ProviderCapabilities struct {
+		// MaxAge is the freshness guarantee, returned inventory could be as
+		// old as max age and zero value means live data.
+		MaxAge time.Duration `json:"max_age"`
+		// Cached provider keeps inventory that can be invalidated.
+		Cached bool `json:"cached"`
+		// Private provider tells private inventory apart from failures.
+		Private bool `json:"private"`
+	}
+)
+
+// InventoryProviderSetup holds config sections and shared dependencies that
+// inventory provider factories picks from by type, eg. phantasm.Config and
+// the redis client.
+type InventoryProviderSetup []any
+
+// SetupValue returns the first setup value of type T.
+func SetupValue[T any](s InventoryProviderSetup) (T, bool) {
+	for _, v := range s {
+		if t, ok := v.(T); ok {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// InventoryProviderFactory creates the inventory provider from its config
+// section and dependencies on the setup.
+type InventoryProviderFactory func(s InventoryProviderSetup) (InventoryProvider, error)
+
+var inventoryProviders = struct {
+	sync.Mutex
+	factories map[string]InventoryProviderFactory
+}{
+	factories: map[string]InventoryProviderFactory{},
+}
+
+// RegisterInventoryProviderFactory makes the inventory provider available by
+// name for verification config, provider packages registers themselves on
+// init like database/sql drivers. Registering the same name twice panics.
+func RegisterInventoryProviderFactory(name string, f InventoryProviderFactory) {
+	inventoryProviders.Lock()
+	defer inventoryProviders.Unlock()
+
+	if f == nil {
+		panic(fmt.Sprintf("inventory provider %s factory is nil", name))
+	}
+	if _, dup := inventoryProviders.factories[name]; dup {
+		panic(fmt.Sprintf("inventory provider %s registered twice", name))
+	}
+	inventoryProviders.factories[name] = f
+}
+
+// NewInventoryProvider creates the registered inventory provider by name
+// from the setup.
+func NewInventoryProvider(name string, s InventoryProviderSetup) (InventoryProvider, error) {
+	inventoryProviders.Lock()
+	f, ok := inventoryProviders.factories[name]
+	inventoryProviders.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("inventory provider %s is not registered", name)
+	}
+
+	p, err := f(s)
+	if err != nil {
+		return nil, fmt.Errorf("could not create inventory provider %s: %s", name, err)
+	}
+	return p, nil
+}
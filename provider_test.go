@@ -0,0 +1,53 @@
+package dotagiftx
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type testInventoryProvider struct{ name string }
+
+func (p testInventoryProvider) Name() string { return p.name }
+
+func (p testInventoryProvider) Capabilities() ProviderCapabilities { return ProviderCapabilities{} }
+
+func (p testInventoryProvider) InventoryAsset(context.Context, string) ([]SteamAsset, error) {
+	return nil, nil
+}
+
+func (p testInventoryProvider) Invalidate(context.Context, string) error { return nil }
+
+func (p testInventoryProvider) Health(context.Context) error { return nil }
+
+type testProviderConfig struct{ name string }
+
+func TestRegisterInventoryProviderFactory(t *testing.T) {
+	RegisterInventoryProviderFactory("test_setup", func(s InventoryProviderSetup) (InventoryProvider, error) {
+		c, ok := SetupValue[testProviderConfig](s)
+		if !ok {
+			return nil, errors.New("config section required")
+		}
+		return testInventoryProvider{c.name}, nil
+	})
+
+	p, err := NewInventoryProvider("test_setup", InventoryProviderSetup{"redis", testProviderConfig{"from_config"}})
+	if err != nil || p.Name() != "from_config" {
+		t.Fatalf("NewInventoryProvider() = %v, %v, want from_config", p, err)
+	}
+	if _, err = NewInventoryProvider("test_setup", nil); err == nil {
+		t.Errorf("NewInventoryProvider() missing config section should error")
+	}
+	if _, err = NewInventoryProvider("test_missing", nil); err == nil {
+		t.Errorf("NewInventoryProvider() unregistered provider should error")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("RegisterInventoryProviderFactory() same name twice should panic")
+		}
+	}()
+	RegisterInventoryProviderFactory("test_setup", func(InventoryProviderSetup) (InventoryProvider, error) {
+		return testInventoryProvider{"test_setup"}, nil
+	})
+}
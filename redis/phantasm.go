@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -14,6 +15,11 @@ const (
 	phantasmRetryAfterKey = "retryafter"
 	phantasmCooldownKey   = "cooldown"
 	phantasmInvHashKey    = "inventoryhash"
+	phantasmStatsKey      = "stats"
+	phantasmWebhookSigKey = "webhooksig"
+	phantasmSavedKey      = "saved"
+
+	phantasmStatsMaxRetries = 10
 )
 
 func (c *Client) Flush(ctx context.Context) error {
@@ -61,3 +67,141 @@ func (c *Client) SetInventoryHash(ctx context.Context, steamID, hash string, ttl
 	key := fmt.Sprintf("%s:%s:%s", phantasmKey, phantasmInvHashKey, steamID)
 	return c.db.Set(ctx, key, hash, ttl).Err()
 }
+
+func (c *Client) CrawlerStats(ctx context.Context, crawlID string) ([]byte, error) {
+	key := fmt.Sprintf("%s:%s:%s", phantasmKey, phantasmStatsKey, crawlID)
+	v, err := c.db.Get(ctx, key).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return v, err
+	}
+	return v, nil
+}
+
+// UpdateCrawlerStats updates crawler stats with optimistic locking so
+// concurrent updates from other services are not lost, update gets retried
+// when the stats changed in between.
+func (c *Client) UpdateCrawlerStats(ctx context.Context, crawlID string, fn func(stats []byte) ([]byte, error)) error {
+	key := fmt.Sprintf("%s:%s:%s", phantasmKey, phantasmStatsKey, crawlID)
+	txf := func(tx *redis.Tx) error {
+		v, err := tx.Get(ctx, key).Bytes()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		b, err := fn(v)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, b, 0)
+			return nil
+		})
+		return err
+	}
+
+	for range phantasmStatsMaxRetries {
+		err := c.db.Watch(ctx, txf, key)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("could not update crawler stats %s: %w", crawlID, redis.TxFailedErr)
+}
+
+func (c *Client) ClaimWebhookSignature(ctx context.Context, signature string, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s:%s:%s", phantasmKey, phantasmWebhookSigKey, signature)
+	return c.db.SetNX(ctx, key, true, ttl).Result()
+}
+
+func (c *Client) PublishInventorySaved(ctx context.Context, steamID string) error {
+	channel := fmt.Sprintf("%s:%s:%s", phantasmKey, phantasmSavedKey, steamID)
+	return c.db.Publish(ctx, channel, steamID).Err()
+}
+
+// SubscribeInventorySaved registers a waiter on the shared saved signal
+// subscription, the subscription is opened once per client and fans out
+// the signal by steam id.
+func (c *Client) SubscribeInventorySaved(ctx context.Context, steamID string) (<-chan struct{}, func() error, error) {
+	c.saved.mu.Lock()
+	defer c.saved.mu.Unlock()
+
+	if c.saved.sub == nil {
+		pattern := fmt.Sprintf("%s:%s:*", phantasmKey, phantasmSavedKey)
+		// shared subscription outlives the request context.
+		sub := c.db.PSubscribe(context.WithoutCancel(ctx), pattern)
+		// wait for subscription confirmation so publish right after is not missed.
+		if _, err := sub.Receive(ctx); err != nil {
+			_ = sub.Close()
+			return nil, nil, err
+		}
+		c.saved.sub = sub
+		go c.saved.fanOut(sub.Channel())
+	}
+
+	saved := c.saved.add(steamID)
+	unsubscribe := func() error {
+		c.saved.remove(steamID, saved)
+		return nil
+	}
+	return saved, unsubscribe, nil
+}
+
+// savedSubscriber fans out inventory saved signals to waiters of steam id.
+type savedSubscriber struct {
+	mu      sync.Mutex
+	sub     *redis.PubSub
+	waiters map[string]map[chan struct{}]struct{}
+}
+
+// add registers a waiter, caller should hold the lock.
+func (s *savedSubscriber) add(steamID string) chan struct{} {
+	if s.waiters == nil {
+		s.waiters = map[string]map[chan struct{}]struct{}{}
+	}
+	if s.waiters[steamID] == nil {
+		s.waiters[steamID] = map[chan struct{}]struct{}{}
+	}
+	saved := make(chan struct{}, 1)
+	s.waiters[steamID][saved] = struct{}{}
+	return saved
+}
+
+func (s *savedSubscriber) remove(steamID string, saved chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.waiters[steamID], saved)
+	if len(s.waiters[steamID]) == 0 {
+		delete(s.waiters, steamID)
+	}
+}
+
+func (s *savedSubscriber) dispatch(steamID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for saved := range s.waiters[steamID] {
+		select {
+		case saved <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// fanOut dispatches published steam ids until the subscription is closed.
+func (s *savedSubscriber) fanOut(messages <-chan *redis.Message) {
+	for msg := range messages {
+		s.dispatch(msg.Payload)
+	}
+}
+
+func (s *savedSubscriber) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sub == nil {
+		return nil
+	}
+	err := s.sub.Close()
+	s.sub = nil
+	return err
+}
@@ -0,0 +1,32 @@
+package redis
+
+import "testing"
+
+func Test_savedSubscriber_dispatch(t *testing.T) {
+	var s savedSubscriber
+	first := s.add("1")
+	second := s.add("1")
+	other := s.add("2")
+
+	s.dispatch("1")
+	s.dispatch("1")
+	for name, saved := range map[string]chan struct{}{"first": first, "second": second} {
+		select {
+		case <-saved:
+		default:
+			t.Errorf("dispatch() %s waiter did not receive saved signal", name)
+		}
+	}
+	select {
+	case <-other:
+		t.Errorf("dispatch() other steam id waiter received saved signal")
+	default:
+	}
+
+	s.remove("1", first)
+	s.remove("1", second)
+	if _, ok := s.waiters["1"]; ok {
+		t.Errorf("remove() waiters of steam id should be cleared")
+	}
+	s.dispatch("1")
+}
@@ -22,8 +22,9 @@ var ctx = context.Background()
 
 // Client represents Redis database client.
 type Client struct {
-	db  *redis.Client
-	cfg Config
+	db    *redis.Client
+	cfg   Config
+	saved savedSubscriber
 }
 
 // New returns a new Redis client.
@@ -38,11 +39,14 @@ func New(c Config) (*Client, error) {
 		return nil, err
 	}
 
-	return &Client{rdb, c}, nil
+	return &Client{db: rdb, cfg: c}, nil
 }
 
 // Close closes database client connection.
 func (c *Client) Close() error {
+	if err := c.saved.close(); err != nil {
+		return err
+	}
 	return c.db.Close()
 }
 
@@ -144,6 +144,7 @@ func (s *catalogStorage) Count(o dotagiftx.FindOpts) (num int, err error) {
 		KeywordFields: s.keywordFields,
 		IndexSorting:  true,
 		Keyword:       o.Keyword,
+		IDs:           o.IDs,
 		Filter:        o.Filter,
 		Sort:          o.Sort,
 	}
@@ -152,6 +153,95 @@ func (s *catalogStorage) Count(o dotagiftx.FindOpts) (num int, err error) {
 	return
 }
 
+// Facets counts catalogs matching the keyword and filter without hero, origin
+// and rarity so each can be counted without its own filter, all facets are
+// counted on a single query.
+func (s *catalogStorage) Facets(o dotagiftx.FindOpts) (*dotagiftx.CatalogFacets, error) {
+	var selected dotagiftx.Catalog
+	if f, ok := o.Filter.(*dotagiftx.Catalog); ok && f != nil {
+		selected = *f
+		rest := *f
+		rest.Hero, rest.Origin, rest.Rarity = "", "", ""
+		o.Filter = &rest
+	}
+	o = dotagiftx.FindOpts{
+		KeywordFields: s.keywordFields,
+		Keyword:       o.Keyword,
+		IDs:           o.IDs,
+		Filter:        o.Filter,
+		Fields:        dotagiftx.CatalogFacetFields,
+	}
+	base := newFindOptsQuery(s.table(), o)
+	all := base.Filter(catalogFacetFilter(selected, ""))
+	q := r.Expr(map[string]interface{}{
+		"heroes":   base.Filter(catalogFacetFilter(selected, "hero")).Group("hero").Count().Ungroup(),
+		"origins":  base.Filter(catalogFacetFilter(selected, "origin")).Group("origin").Count().Ungroup(),
+		"rarities": base.Filter(catalogFacetFilter(selected, "rarity")).Group("rarity").Count().Ungroup(),
+		"prices": all.Filter(r.Row.Field("lowest_ask").Gt(0)).
+			Group(func(t r.Term) r.Term { return catalogPriceBucket(t.Field("lowest_ask")) }).
+			Count().Ungroup(),
+		"has_bids": all.Filter(r.Row.Field("bid_count").Gt(0)).Count(),
+	})
+
+	var res struct {
+		Heroes   []facetGroup[string] `db:"heroes"`
+		Origins  []facetGroup[string] `db:"origins"`
+		Rarities []facetGroup[string] `db:"rarities"`
+		Prices   []facetGroup[int]    `db:"prices"`
+		HasBids  int                  `db:"has_bids"`
+	}
+	if err := s.db.one(q, &res); err != nil {
+		return nil, dotagiftx.NewXError(dotagiftx.StorageUncaughtErr, err)
+	}
+	return dotagiftx.NewCatalogFacets(dotagiftx.CatalogFacetCounts{
+		Heroes:   facetGroupCounts(res.Heroes),
+		Origins:  facetGroupCounts(res.Origins),
+		Rarities: facetGroupCounts(res.Rarities),
+		Prices:   facetGroupCounts(res.Prices),
+		HasBids:  res.HasBids,
+	}), nil
+}
+
+// facetGroup represents an ungrouped count result.
+type facetGroup[T comparable] struct {
+	Group     T   `db:"group"`
+	Reduction int `db:"reduction"`
+}
+
+func facetGroupCounts[T comparable](groups []facetGroup[T]) map[T]int {
+	m := make(map[T]int, len(groups))
+	for _, g := range groups {
+		m[g.Group] = g.Reduction
+	}
+	return m
+}
+
+// catalogFacetFilter returns selected hero, origin and rarity filter without
+// the facet field.
+func catalogFacetFilter(selected dotagiftx.Catalog, facet string) map[string]interface{} {
+	f := map[string]interface{}{}
+	for field, v := range map[string]string{
+		"hero":   selected.Hero,
+		"origin": selected.Origin,
+		"rarity": selected.Rarity,
+	} {
+		if v != "" && field != facet {
+			f[field] = v
+		}
+	}
+	return f
+}
+
+// catalogPriceBucket returns the lowest ask price range index.
+func catalogPriceBucket(price r.Term) r.Term {
+	b := dotagiftx.CatalogPriceBuckets
+	var args []interface{}
+	for i := len(b) - 1; i > 0; i-- {
+		args = append(args, price.Ge(b[i]), i)
+	}
+	return r.Branch(append(args, 0)...)
+}
+
 func (s *catalogStorage) Get(id string) (*dotagiftx.Catalog, error) {
 	row, _ := s.getBySlug(id)
 	if row != nil {
@@ -22,15 +22,29 @@ func baseFindOptsQuery(q r.Term, o dotagiftx.FindOpts, hookFn func(r.Term) r.Ter
 func (o findOpts) parseOpts(q r.Term, hookFn func(r.Term) r.Term) r.Term {
 	// Use index query instead of filter if available and disable indexed sorting.
 	filter := o.parseFilter()
+	var indexed bool
 	if o.IndexKey != "" {
 		v, ok := filter[o.IndexKey]
 		if ok {
 			q = q.GetAllByIndex(o.IndexKey, v)
 			delete(filter, o.IndexKey)
 			o.IndexSorting = false
+			indexed = true
 		}
 	}
 
+	// Use primary key query on ids when there is no index query.
+	ids := o.IDs
+	if len(ids) != 0 && !indexed {
+		keys := make([]interface{}, len(ids))
+		for i, id := range ids {
+			keys[i] = id
+		}
+		q = q.GetAll(keys...)
+		o.IndexSorting = false
+		ids = nil
+	}
+
 	if o.IndexSorting && o.Sort != "" {
 		q = q.OrderBy(r.OrderByOpts{Index: o.parseOrder()})
 	}
@@ -43,6 +57,12 @@ func (o findOpts) parseOpts(q r.Term, hookFn func(r.Term) r.Term) r.Term {
 		q = q.Filter(o.parseKeyword())
 	}
 
+	if len(ids) != 0 {
+		q = q.Filter(func(t r.Term) r.Term {
+			return r.Expr(ids).Contains(t.Field("id"))
+		})
+	}
+
 	if o.Filter != nil {
 		q = q.Filter(filter)
 	}
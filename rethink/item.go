@@ -51,6 +51,7 @@ func (s *itemStorage) Count(o dotagiftx.FindOpts) (num int, err error) {
 	o = dotagiftx.FindOpts{
 		Keyword:       o.Keyword,
 		KeywordFields: s.keywordFields,
+		IDs:           o.IDs,
 		Filter:        o.Filter,
 		UserID:        o.UserID,
 	}
@@ -124,6 +125,19 @@ func (s *itemStorage) Update(in *dotagiftx.Item) error {
 	return nil
 }
 
+func (s *itemStorage) UpdateAliases(id string, aliases dotagiftx.ItemAliases) error {
+	// Literal replaces the whole aliases object instead of merging them.
+	q := s.table().Get(id).Update(map[string]interface{}{
+		"aliases":    r.Literal(aliases),
+		"updated_at": now(),
+	})
+	if err := s.db.update(q); err != nil {
+		return dotagiftx.NewXError(dotagiftx.StorageUncaughtErr, err)
+	}
+
+	return nil
+}
+
 func (s *itemStorage) IsItemExist(name string) error {
 	/*
 		r.table('item').filter(function(doc) {
@@ -0,0 +1,93 @@
+package rethink
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/kudarap/dotagiftx"
+	r "gopkg.in/rethinkdb/rethinkdb-go.v6"
+)
+
+const (
+	tableSnapshot          = "inventory_snapshot"
+	snapshotFieldSteamID   = "steam_id"
+	snapshotFieldCreatedAt = "created_at"
+)
+
+// NewSnapshot creates new instance of inventory snapshot data store.
+func NewSnapshot(c *Client) dotagiftx.InventorySnapshotStorage {
+	if err := c.autoMigrate(tableSnapshot); err != nil {
+		log.Fatalf("could not create %s table: %s", tableSnapshot, err)
+	}
+
+	if err := c.autoIndex(tableSnapshot, dotagiftx.InventorySnapshot{}); err != nil {
+		log.Fatalf("could not create index on %s table: %s", tableSnapshot, err)
+	}
+
+	return &snapshotStorage{c}
+}
+
+type snapshotStorage struct {
+	db *Client
+}
+
+func (s *snapshotStorage) Find(steamID string, o dotagiftx.FindOpts) ([]dotagiftx.InventorySnapshot, error) {
+	q := s.table().GetAllByIndex(snapshotFieldSteamID, steamID).OrderBy(r.Desc(snapshotFieldCreatedAt))
+	if o.Limit > 0 {
+		q = q.Skip(o.Page * o.Limit).Limit(o.Limit)
+	}
+
+	var res []dotagiftx.InventorySnapshot
+	if err := s.db.list(q, &res); err != nil {
+		return nil, dotagiftx.NewXError(dotagiftx.StorageUncaughtErr, err)
+	}
+
+	return res, nil
+}
+
+func (s *snapshotStorage) Latest(steamID string, before time.Time) (*dotagiftx.InventorySnapshot, error) {
+	q := s.table().GetAllByIndex(snapshotFieldSteamID, steamID).
+		Filter(r.Row.Field(snapshotFieldCreatedAt).Le(before)).
+		OrderBy(r.Desc(snapshotFieldCreatedAt)).
+		Limit(1)
+
+	row := &dotagiftx.InventorySnapshot{}
+	if err := s.db.one(q, row); err != nil {
+		if errors.Is(err, r.ErrEmptyResult) {
+			return nil, dotagiftx.SnapshotErrNotFound
+		}
+
+		return nil, dotagiftx.NewXError(dotagiftx.StorageUncaughtErr, err)
+	}
+
+	return row, nil
+}
+
+func (s *snapshotStorage) Create(in *dotagiftx.InventorySnapshot) error {
+	in.CreatedAt = now()
+	in.ID = ""
+	id, err := s.db.insert(s.table().Insert(in))
+	if err != nil {
+		return dotagiftx.NewXError(dotagiftx.StorageUncaughtErr, err)
+	}
+	in.ID = id
+
+	return nil
+}
+
+func (s *snapshotStorage) Prune(steamID string, keep int) error {
+	q := s.table().GetAllByIndex(snapshotFieldSteamID, steamID).
+		OrderBy(r.Desc(snapshotFieldCreatedAt)).
+		Skip(keep).
+		Delete()
+	if err := s.db.delete(q); err != nil && !errors.Is(err, r.ErrEmptyResult) {
+		return dotagiftx.NewXError(dotagiftx.StorageUncaughtErr, err)
+	}
+
+	return nil
+}
+
+func (s *snapshotStorage) table() r.Term {
+	return r.Table(tableSnapshot)
+}
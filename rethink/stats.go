@@ -98,6 +98,8 @@ func (s *statsStorage) CountUserMarketStatus(userID string) (*dotagiftx.MarketSt
 	marketStats.DeliveryNoHit = dlvMap[dotagiftx.DeliveryStatusNoHit]
 	marketStats.DeliveryNameVerified = dlvMap[dotagiftx.DeliveryStatusNameVerified]
 	marketStats.DeliverySenderVerified = dlvMap[dotagiftx.DeliveryStatusSenderVerified]
+	marketStats.DeliveryTransferVerified = dlvMap[dotagiftx.DeliveryStatusTransferVerified]
+	marketStats.DeliveryBackdated = dlvMap[dotagiftx.DeliveryStatusBackdated]
 	marketStats.DeliveryPrivate = dlvMap[dotagiftx.DeliveryStatusPrivate]
 	marketStats.DeliveryError = dlvMap[dotagiftx.DeliveryStatusError]
 	s.logger.Println("rethink/stats count dlv", time.Since(benchStart))
@@ -117,6 +119,7 @@ func (s *statsStorage) CountUserMarketStatus(userID string) (*dotagiftx.MarketSt
 	}
 	marketStats.InventoryNoHit = invMap[dotagiftx.InventoryStatusNoHit]
 	marketStats.InventoryVerified = invMap[dotagiftx.InventoryStatusVerified]
+	marketStats.InventoryOverQuantity = invMap[dotagiftx.InventoryStatusOverQuantity]
 	marketStats.InventoryPrivate = invMap[dotagiftx.InventoryStatusPrivate]
 	marketStats.InventoryError = invMap[dotagiftx.InventoryStatusError]
 	s.logger.Println("rethink/stats count inv", time.Since(benchStart))
@@ -186,6 +189,7 @@ func (s *statsStorage) CountMarketStatus(opts dotagiftx.FindOpts) (*dotagiftx.Ma
 	}
 	marketStats.InventoryNoHit = inventoryStats.InventoryNoHit
 	marketStats.InventoryVerified = inventoryStats.InventoryVerified
+	marketStats.InventoryOverQuantity = inventoryStats.InventoryOverQuantity
 	marketStats.InventoryPrivate = inventoryStats.InventoryPrivate
 	marketStats.InventoryError = inventoryStats.InventoryError
 
@@ -206,11 +210,13 @@ func (s *statsStorage) countDeliveryStatus(o dotagiftx.FindOpts) (*dotagiftx.Mar
 		dlvMap[v.Status] = v.Count
 	}
 	msc := &dotagiftx.MarketStatusCount{
-		DeliveryNoHit:          dlvMap[dotagiftx.DeliveryStatusNoHit],
-		DeliveryNameVerified:   dlvMap[dotagiftx.DeliveryStatusNameVerified],
-		DeliverySenderVerified: dlvMap[dotagiftx.DeliveryStatusSenderVerified],
-		DeliveryPrivate:        dlvMap[dotagiftx.DeliveryStatusPrivate],
-		DeliveryError:          dlvMap[dotagiftx.DeliveryStatusError],
+		DeliveryNoHit:            dlvMap[dotagiftx.DeliveryStatusNoHit],
+		DeliveryNameVerified:     dlvMap[dotagiftx.DeliveryStatusNameVerified],
+		DeliverySenderVerified:   dlvMap[dotagiftx.DeliveryStatusSenderVerified],
+		DeliveryTransferVerified: dlvMap[dotagiftx.DeliveryStatusTransferVerified],
+		DeliveryBackdated:        dlvMap[dotagiftx.DeliveryStatusBackdated],
+		DeliveryPrivate:          dlvMap[dotagiftx.DeliveryStatusPrivate],
+		DeliveryError:            dlvMap[dotagiftx.DeliveryStatusError],
 	}
 
 	return msc, nil
@@ -231,10 +237,11 @@ func (s *statsStorage) countInventoryStatus(o dotagiftx.FindOpts) (*dotagiftx.Ma
 	}
 
 	msc := &dotagiftx.MarketStatusCount{
-		InventoryNoHit:    mapRes[dotagiftx.InventoryStatusNoHit],
-		InventoryVerified: mapRes[dotagiftx.InventoryStatusVerified],
-		InventoryPrivate:  mapRes[dotagiftx.InventoryStatusPrivate],
-		InventoryError:    mapRes[dotagiftx.InventoryStatusError],
+		InventoryNoHit:        mapRes[dotagiftx.InventoryStatusNoHit],
+		InventoryVerified:     mapRes[dotagiftx.InventoryStatusVerified],
+		InventoryOverQuantity: mapRes[dotagiftx.InventoryStatusOverQuantity],
+		InventoryPrivate:      mapRes[dotagiftx.InventoryStatusPrivate],
+		InventoryError:        mapRes[dotagiftx.InventoryStatusError],
 	}
 
 	return msc, nil
@@ -81,17 +81,20 @@ func (s *statsStorage) CountMarketStatusV2(opts dotagiftx.FindOpts) (*dotagiftx.
 		BidCompleted: statusResult[dotagiftx.MarketStatusBidCompleted],
 
 		// delivery stats
-		DeliveryNoHit:          deliveryResult[dotagiftx.DeliveryStatusNoHit],
-		DeliveryNameVerified:   deliveryResult[dotagiftx.DeliveryStatusNameVerified],
-		DeliverySenderVerified: deliveryResult[dotagiftx.DeliveryStatusSenderVerified],
-		DeliveryPrivate:        deliveryResult[dotagiftx.DeliveryStatusPrivate],
-		DeliveryError:          deliveryResult[dotagiftx.DeliveryStatusError],
+		DeliveryNoHit:            deliveryResult[dotagiftx.DeliveryStatusNoHit],
+		DeliveryNameVerified:     deliveryResult[dotagiftx.DeliveryStatusNameVerified],
+		DeliverySenderVerified:   deliveryResult[dotagiftx.DeliveryStatusSenderVerified],
+		DeliveryTransferVerified: deliveryResult[dotagiftx.DeliveryStatusTransferVerified],
+		DeliveryBackdated:        deliveryResult[dotagiftx.DeliveryStatusBackdated],
+		DeliveryPrivate:          deliveryResult[dotagiftx.DeliveryStatusPrivate],
+		DeliveryError:            deliveryResult[dotagiftx.DeliveryStatusError],
 
 		// inventory stats
-		InventoryNoHit:    inventoryResult[dotagiftx.InventoryStatusNoHit],
-		InventoryVerified: inventoryResult[dotagiftx.InventoryStatusVerified],
-		InventoryPrivate:  inventoryResult[dotagiftx.InventoryStatusPrivate],
-		InventoryError:    inventoryResult[dotagiftx.InventoryStatusError],
+		InventoryNoHit:        inventoryResult[dotagiftx.InventoryStatusNoHit],
+		InventoryVerified:     inventoryResult[dotagiftx.InventoryStatusVerified],
+		InventoryOverQuantity: inventoryResult[dotagiftx.InventoryStatusOverQuantity],
+		InventoryPrivate:      inventoryResult[dotagiftx.InventoryStatusPrivate],
+		InventoryError:        inventoryResult[dotagiftx.InventoryStatusError],
 
 		// resell stats
 		ResellLive:      resellResult[dotagiftx.MarketStatusLive],
@@ -0,0 +1,313 @@
+// Package search provides in-process typo-tolerant keyword search of items
+// and catalogs.
+//
+// Documents are tokenized into lowercase terms with adjacent words joined,
+// each keyword word matches terms by the best available tier:
+//   - exact term or synonym phrase like "am" for Anti-Mage.
+//   - term prefix for partially typed words.
+//   - fuzzy term within edit distance using trigram candidates.
+//
+// Index is loaded from storage and kept updated by table change feeds.
+package search
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Match tier scores, every keyword word adds the best score of its tier.
+const (
+	scoreExact  = 3
+	scorePrefix = 2
+	scoreFuzzy  = 1
+
+	// minPrefixLen is the shortest keyword word to match by prefix.
+	minPrefixLen = 2
+)
+
+// Document represents a searchable item, Names holds alternate names and
+// known misspellings including the item name.
+type Document struct {
+	ID     string
+	Names  []string
+	Hero   string
+	Origin string
+	Rarity string
+}
+
+func (d Document) terms() []string {
+	var res []string
+	for _, s := range append(slices.Clone(d.Names), d.Hero, d.Origin, d.Rarity) {
+		res = append(res, terms(s)...)
+	}
+	slices.Sort(res)
+	return slices.Compact(res)
+}
+
+// Index represents an in-memory inverted index of documents.
+type Index struct {
+	synonyms map[string][]string
+
+	mu     sync.RWMutex
+	docs   map[string]Document
+	terms  map[string]map[string]struct{} // term to document ids
+	grams  map[string]map[string]struct{} // trigram to terms
+	loaded bool
+	// pending are changes received before the index is loaded.
+	pending []func()
+}
+
+// NewIndex returns an empty index with synonyms of word to phrases.
+func NewIndex(synonyms map[string][]string) *Index {
+	syn := map[string][]string{}
+	for k, v := range synonyms {
+		key := strings.Join(tokenize(k), "")
+		for _, p := range v {
+			syn[key] = append(syn[key], strings.Join(tokenize(p), " "))
+		}
+	}
+	return &Index{
+		synonyms: syn,
+		docs:     map[string]Document{},
+		terms:    map[string]map[string]struct{}{},
+		grams:    map[string]map[string]struct{}{},
+	}
+}
+
+// Load puts the documents and marks the index ready for matching, pending
+// changes are applied after so the documents does not replay over them.
+func (x *Index) Load(docs []Document) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	for _, d := range docs {
+		x.put(d)
+	}
+	for len(x.pending) != 0 {
+		pending := x.pending
+		x.pending = nil
+		x.mu.Unlock()
+		for _, fn := range pending {
+			fn()
+		}
+		x.mu.Lock()
+	}
+	x.loaded = true
+}
+
+// change applies the change when the index is loaded, otherwise it's kept
+// pending until the index loads.
+func (x *Index) change(fn func()) {
+	x.mu.Lock()
+	if !x.loaded {
+		x.pending = append(x.pending, fn)
+		x.mu.Unlock()
+		return
+	}
+	x.mu.Unlock()
+	fn()
+}
+
+// Put adds or replaces the document.
+func (x *Index) Put(d Document) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.put(d)
+}
+
+// Get returns the indexed document by id.
+func (x *Index) Get(id string) (Document, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	d, ok := x.docs[id]
+	return d, ok
+}
+
+// Delete removes the document, missing document is ignored.
+func (x *Index) Delete(id string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.delete(id)
+}
+
+// Len returns number of indexed documents.
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return len(x.docs)
+}
+
+func (x *Index) put(d Document) {
+	x.delete(d.ID)
+	x.docs[d.ID] = d
+	for _, t := range d.terms() {
+		ids, ok := x.terms[t]
+		if !ok {
+			ids = map[string]struct{}{}
+			x.terms[t] = ids
+			for _, g := range trigrams(t) {
+				if x.grams[g] == nil {
+					x.grams[g] = map[string]struct{}{}
+				}
+				x.grams[g][t] = struct{}{}
+			}
+		}
+		ids[d.ID] = struct{}{}
+	}
+}
+
+func (x *Index) delete(id string) {
+	d, ok := x.docs[id]
+	if !ok {
+		return
+	}
+	delete(x.docs, id)
+	for _, t := range d.terms() {
+		delete(x.terms[t], id)
+		if len(x.terms[t]) != 0 {
+			continue
+		}
+		delete(x.terms, t)
+		for _, g := range trigrams(t) {
+			delete(x.grams[g], t)
+			if len(x.grams[g]) == 0 {
+				delete(x.grams, g)
+			}
+		}
+	}
+}
+
+// Match returns ids of documents matching all keyword words ordered by
+// relevance, ok is false when the index is not loaded yet or keyword has
+// no words.
+func (x *Index) Match(keyword string) (ids []string, ok bool) {
+	words := tokenize(keyword)
+	if len(words) == 0 {
+		return nil, false
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	if !x.loaded {
+		return nil, false
+	}
+
+	var scores map[string]int
+	for _, w := range words {
+		matches := x.matchWord(w)
+		if scores == nil {
+			scores = matches
+			continue
+		}
+		for id, s := range scores {
+			if m, ok := matches[id]; ok {
+				scores[id] = s + m
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	ids = make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b string) int {
+		if c := cmp.Compare(scores[b], scores[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	return ids, true
+}
+
+// matchWord returns document scores of the best matching tier of the word.
+func (x *Index) matchWord(w string) map[string]int {
+	res := map[string]int{}
+	add := func(ids map[string]struct{}, score int) {
+		for id := range ids {
+			res[id] = max(res[id], score)
+		}
+	}
+
+	add(x.terms[w], scoreExact)
+	for _, phrase := range x.synonyms[w] {
+		add(x.matchPhrase(phrase), scoreExact)
+	}
+	// synonym words like "am" are intended and not a partial word.
+	if len(res) != 0 || len(x.synonyms[w]) != 0 {
+		return res
+	}
+
+	if len([]rune(w)) >= minPrefixLen {
+		prefix := trigrams(w)[0]
+		for t := range x.grams[prefix] {
+			if strings.HasPrefix(t, w) {
+				add(x.terms[t], scorePrefix)
+			}
+		}
+		if len(res) != 0 {
+			return res
+		}
+	}
+
+	for _, t := range x.fuzzyTerms(w) {
+		add(x.terms[t], scoreFuzzy)
+	}
+	return res
+}
+
+// matchPhrase returns documents with all the phrase words as exact terms.
+func (x *Index) matchPhrase(phrase string) map[string]struct{} {
+	var res map[string]struct{}
+	for _, w := range strings.Fields(phrase) {
+		ids := x.terms[w]
+		if res == nil {
+			res = make(map[string]struct{}, len(ids))
+			for id := range ids {
+				res[id] = struct{}{}
+			}
+			continue
+		}
+		for id := range res {
+			if _, ok := ids[id]; !ok {
+				delete(res, id)
+			}
+		}
+	}
+	return res
+}
+
+// fuzzyTerms returns terms within allowed edits of the word. Candidates
+// are terms sharing enough trigrams since an edit changes at most four of
+// them.
+func (x *Index) fuzzyTerms(w string) []string {
+	edits := maxEdits(w)
+	if edits == 0 {
+		return nil
+	}
+
+	grams := trigrams(w)
+	shared := map[string]int{}
+	for _, g := range grams {
+		for t := range x.grams[g] {
+			shared[t]++
+		}
+	}
+	var res []string
+	for t, n := range shared {
+		if n < len(grams)-4*edits {
+			continue
+		}
+		if editDistance(w, t, edits) <= edits {
+			res = append(res, t)
+		}
+	}
+	return res
+}
@@ -0,0 +1,191 @@
+package search
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/kudarap/dotagiftx"
+)
+
+func testIndex(t *testing.T) *Index {
+	t.Helper()
+	x := NewIndex(DefaultSynonyms())
+	x.Load([]Document{
+		ItemDocument(dotagiftx.Item{ID: "1", Name: "Manifold Paradox", Hero: "Phantom Assassin", Origin: "Immortal Treasure I"}),
+		ItemDocument(dotagiftx.Item{ID: "2", Name: "Fury of the Righteous Storm", Hero: "Anti-Mage", Origin: "Collector's Cache"}),
+		ItemDocument(dotagiftx.Item{ID: "3", Name: "Intergalactic Obliterator", Hero: "Underlord", Origin: "Immortal Treasure II"}),
+		ItemDocument(dotagiftx.Item{ID: "4", Name: "Mage Slayer", Hero: "Anti-Mage", Origin: "Immortal Treasure III"}),
+		ItemDocument(dotagiftx.Item{ID: "5", Name: "Crimson Witness", Hero: "Shadow Fiend", Origin: "Collector's Cache"}),
+		ItemDocument(dotagiftx.Item{ID: "6", Name: "Stormcrafter", Hero: "Storm Spirit", Origin: "Immortal Treasure I"}),
+	})
+	return x
+}
+
+func TestIndex_Match(t *testing.T) {
+	x := testIndex(t)
+	tests := []struct {
+		keyword string
+		want    []string
+	}{
+		{"antimage", []string{"2", "4"}},
+		{"Anti-Mage", []string{"2", "4"}},
+		{"AM", []string{"2", "4"}},
+		{"anti mage slayer", []string{"4"}},
+		{"Orbliterator", []string{"3"}},
+		{"abyssalunderlord", []string{"3"}},
+		{"shadowfiend", []string{"5"}},
+		{"nevermore", []string{"5"}},
+		{"collectors cache", []string{"2", "5"}},
+		{"collector's", []string{"2", "5"}},
+		{"manifld", []string{"1"}},
+		{"mani", []string{"1"}},
+		{"storm", []string{"2", "6"}},
+		{"paradox mage", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.keyword, func(t *testing.T) {
+			got, ok := x.Match(tt.keyword)
+			if !ok {
+				t.Fatal("Match() not ok")
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndex_Match_notLoaded(t *testing.T) {
+	x := NewIndex(nil)
+	x.Put(Document{ID: "1", Names: []string{"Manifold Paradox"}})
+	if _, ok := x.Match("manifold"); ok {
+		t.Error("Match() should not be ok before load")
+	}
+	x.Load(nil)
+	if _, ok := x.Match(" - "); ok {
+		t.Error("Match() should not be ok without words")
+	}
+	if got, _ := x.Match("manifold"); !slices.Equal(got, []string{"1"}) {
+		t.Errorf("Match() = %v, want [1]", got)
+	}
+}
+
+func TestIndex_changeFeed(t *testing.T) {
+	x := testIndex(t)
+	item := dotagiftx.Item{
+		ID:      "3",
+		Name:    "Intergalactic Obliterator",
+		Hero:    "Underlord",
+		Aliases: &dotagiftx.ItemAliases{Names: []string{"Galactic Crusher"}},
+	}
+	next, _ := json.Marshal(item)
+	if err := x.ItemChange(nil, next); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := x.Match("crusher"); !slices.Equal(got, []string{"3"}) {
+		t.Errorf("Match() item alias = %v, want [3]", got)
+	}
+
+	// catalog update keeps item aliases.
+	next, _ = json.Marshal(dotagiftx.Catalog{ID: "3", Name: "Intergalactic Obliterator", Hero: "Underlord", Rarity: "immortal"})
+	if err := x.CatalogChange(nil, next); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := x.Match("crusher immortal"); !slices.Equal(got, []string{"3"}) {
+		t.Errorf("Match() after catalog change = %v, want [3]", got)
+	}
+
+	if err := x.ItemChange(next, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := x.Match("crusher"); len(got) != 0 {
+		t.Errorf("Match() deleted = %v, want none", got)
+	}
+	if n := x.Len(); n != 5 {
+		t.Errorf("Len() = %d, want 5", n)
+	}
+	if _, ok := x.terms["crusher"]; ok {
+		t.Error("deleted document terms should be removed")
+	}
+}
+
+func TestIndex_changeFeedBeforeLoad(t *testing.T) {
+	x := NewIndex(nil)
+	renamed, _ := json.Marshal(dotagiftx.Item{ID: "1", Name: "Manifold Paradox", Hero: "Phantom Assassin"})
+	removed, _ := json.Marshal(dotagiftx.Item{ID: "2", Name: "Fractal Horns of Inner Abysm"})
+	if err := x.ItemChange(nil, renamed); err != nil {
+		t.Fatal(err)
+	}
+	if err := x.ItemChange(removed, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := x.Len(); n != 0 {
+		t.Fatalf("Len() before load = %d, want changes pending", n)
+	}
+
+	// Loaded documents are older than the changes.
+	x.Load([]Document{
+		{ID: "1", Names: []string{"Manifold"}},
+		{ID: "2", Names: []string{"Fractal Horns of Inner Abysm"}},
+	})
+	if got, _ := x.Match("paradox"); !slices.Equal(got, []string{"1"}) {
+		t.Errorf("Match() changed = %v, want [1]", got)
+	}
+	if got, _ := x.Match("fractal"); len(got) != 0 {
+		t.Errorf("Match() deleted = %v, want none", got)
+	}
+	if n := x.Len(); n != 1 {
+		t.Errorf("Len() = %d, want 1", n)
+	}
+}
+
+func TestIndex_findOpts(t *testing.T) {
+	x := testIndex(t)
+	o := x.findOpts(dotagiftx.FindOpts{Keyword: "orbliterator", Limit: 10})
+	if o.Keyword != "" || !slices.Equal(o.IDs, []string{"3"}) || o.Limit != 10 {
+		t.Errorf("findOpts() = %+v, want keyword replaced by ids", o)
+	}
+	o = x.findOpts(dotagiftx.FindOpts{Keyword: "zzzz"})
+	if o.Keyword != "zzzz" || o.IDs != nil {
+		t.Errorf("findOpts() no match = %+v, want keyword kept", o)
+	}
+}
+
+func Test_editDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		max  int
+		want int
+	}{
+		{"obliterator", "orbliterator", 2, 1},
+		{"manifold", "mainfold", 2, 1},
+		{"paradox", "paradox", 1, 0},
+		{"kitten", "sitting", 3, 3},
+		{"kitten", "sitting", 1, 2},
+		{"axe", "axeaxeaxe", 2, 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.max); got != tt.want {
+			t.Errorf("editDistance(%s, %s, %d) = %d, want %d", tt.a, tt.b, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultSynonyms(t *testing.T) {
+	syn := DefaultSynonyms()
+	for alias, want := range map[string]string{
+		"am":        "anti mage",
+		"qop":       "queen of pain",
+		"furion":    "natures prophet",
+		"nevermore": "shadow fiend",
+	} {
+		if !slices.Contains(syn[alias], want) {
+			t.Errorf("DefaultSynonyms()[%s] = %v, want %s", alias, syn[alias], want)
+		}
+	}
+	if _, ok := syn["axe"]; ok {
+		t.Error("DefaultSynonyms() should skip id same as name")
+	}
+}
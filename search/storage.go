@@ -0,0 +1,126 @@
+package search
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/kudarap/dotagiftx"
+)
+
+// maxMatchIDs keeps broad keywords on storage keyword matching instead of
+// a large id filter.
+const maxMatchIDs = 1000
+
+// NewItemStorage returns item storage that finds keywords using the index.
+func NewItemStorage(x *Index, is dotagiftx.ItemStorage) dotagiftx.ItemStorage {
+	return &itemStorage{is, x}
+}
+
+type itemStorage struct {
+	dotagiftx.ItemStorage
+	index *Index
+}
+
+func (s *itemStorage) Find(o dotagiftx.FindOpts) ([]dotagiftx.Item, error) {
+	return s.ItemStorage.Find(s.index.findOpts(o))
+}
+
+func (s *itemStorage) Count(o dotagiftx.FindOpts) (int, error) {
+	return s.ItemStorage.Count(s.index.findOpts(o))
+}
+
+// NewCatalogStorage returns catalog storage that finds keywords using the
+// index, catalogs share the same id with their items.
+func NewCatalogStorage(x *Index, cs dotagiftx.CatalogStorage) dotagiftx.CatalogStorage {
+	return &catalogStorage{cs, x}
+}
+
+type catalogStorage struct {
+	dotagiftx.CatalogStorage
+	index *Index
+}
+
+func (s *catalogStorage) Find(o dotagiftx.FindOpts) ([]dotagiftx.Catalog, error) {
+	return s.CatalogStorage.Find(s.index.findOpts(o))
+}
+
+func (s *catalogStorage) Count(o dotagiftx.FindOpts) (int, error) {
+	return s.CatalogStorage.Count(s.index.findOpts(o))
+}
+
+func (s *catalogStorage) Facets(o dotagiftx.FindOpts) (*dotagiftx.CatalogFacets, error) {
+	return s.CatalogStorage.Facets(s.index.findOpts(o))
+}
+
+// findOpts replaces the keyword with matching ids, keyword is kept for
+// storage matching when index has no match or too many of them.
+func (x *Index) findOpts(o dotagiftx.FindOpts) dotagiftx.FindOpts {
+	if strings.TrimSpace(o.Keyword) == "" {
+		return o
+	}
+	ids, ok := x.Match(o.Keyword)
+	if !ok || len(ids) == 0 || len(ids) > maxMatchIDs {
+		return o
+	}
+	o.Keyword = ""
+	o.IDs = ids
+	return o
+}
+
+// ItemDocument returns searchable document of the item and its aliases.
+func ItemDocument(i dotagiftx.Item) Document {
+	return Document{
+		ID:     i.ID,
+		Names:  i.MatchAliases().MatchNames(i.Name),
+		Hero:   i.Hero,
+		Origin: i.Origin,
+		Rarity: i.Rarity,
+	}
+}
+
+// ItemChange applies item table change feed event on the index.
+func (x *Index) ItemChange(prev, next []byte) error {
+	if next == nil {
+		var old dotagiftx.Item
+		if err := json.Unmarshal(prev, &old); err != nil {
+			return err
+		}
+		x.change(func() { x.Delete(old.ID) })
+		return nil
+	}
+
+	var i dotagiftx.Item
+	if err := json.Unmarshal(next, &i); err != nil {
+		return err
+	}
+	x.change(func() { x.Put(ItemDocument(i)) })
+	return nil
+}
+
+// CatalogChange applies catalog table change feed event on the index.
+// Catalogs are indexed copies of items so removed catalogs stays searchable
+// as items and item aliases are kept.
+func (x *Index) CatalogChange(_, next []byte) error {
+	if next == nil {
+		return nil
+	}
+
+	var c dotagiftx.Catalog
+	if err := json.Unmarshal(next, &c); err != nil {
+		return err
+	}
+	x.change(func() { x.putCatalog(c) })
+	return nil
+}
+
+func (x *Index) putCatalog(c dotagiftx.Catalog) {
+	d := Document{ID: c.ID, Names: []string{c.Name}, Hero: c.Hero, Origin: c.Origin, Rarity: c.Rarity}
+	if cur, ok := x.Get(c.ID); ok {
+		if cur.Hero == d.Hero && cur.Origin == d.Origin && cur.Rarity == d.Rarity &&
+			len(cur.Names) != 0 && cur.Names[0] == c.Name {
+			return
+		}
+		d.Names = append(d.Names, cur.Names[min(1, len(cur.Names)):]...)
+	}
+	x.Put(d)
+}
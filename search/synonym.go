@@ -0,0 +1,40 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/kudarap/dotagiftx/dota2"
+)
+
+// DefaultSynonyms returns hero abbreviations and internal ids like "am",
+// "qop" and "nevermore" mapped to hero names. An abbreviation could map to
+// multiple heroes.
+func DefaultSynonyms() map[string][]string {
+	syn := map[string][]string{}
+	add := func(alias, phrase string) {
+		if alias == "" || alias == phrase {
+			return
+		}
+		for _, p := range syn[alias] {
+			if p == phrase {
+				return
+			}
+		}
+		syn[alias] = append(syn[alias], phrase)
+	}
+
+	for _, h := range dota2.AllHeroes {
+		tokens := tokenize(h.Name)
+		name := strings.Join(tokens, " ")
+		add(strings.ToLower(h.ID), name)
+		if len(tokens) < 2 {
+			continue
+		}
+		var initials strings.Builder
+		for _, t := range tokens {
+			initials.WriteString(string([]rune(t)[0]))
+		}
+		add(initials.String(), name)
+	}
+	return syn
+}
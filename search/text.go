@@ -0,0 +1,93 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+var apostrophes = strings.NewReplacer("'", "", "’", "")
+
+// tokenize splits text into lowercase words, apostrophes are dropped so
+// "Collector's" matches "collectors".
+func tokenize(s string) []string {
+	s = apostrophes.Replace(strings.ToLower(s))
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// terms returns text tokens with adjacent tokens joined so "Anti-Mage"
+// and "Shadow Fiend" also matches "antimage" and "shadowfiend".
+func terms(s string) []string {
+	tokens := tokenize(s)
+	res := tokens
+	for i := 1; i < len(tokens); i++ {
+		res = append(res, tokens[i-1]+tokens[i])
+	}
+	return res
+}
+
+// trigrams returns rune trigrams of the term padded with start and end
+// markers, the first trigram is shared by terms with the same two starting
+// runes.
+func trigrams(term string) []string {
+	r := []rune("^" + term + "$")
+	if len(r) < 3 {
+		return nil
+	}
+	res := make([]string, 0, len(r)-2)
+	for i := 0; i+3 <= len(r); i++ {
+		res = append(res, string(r[i:i+3]))
+	}
+	return res
+}
+
+// maxEdits returns allowed typos by term length, short terms must match
+// exactly or by prefix.
+func maxEdits(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance returns optimal string alignment distance of a and b where
+// adjacent transposition counts as one edit, it stops early and returns
+// max+1 when distance exceeds max.
+func editDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > max || -d > max {
+		return max + 1
+	}
+
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return min(prev[len(rb)], max+1)
+}
@@ -0,0 +1,245 @@
+package dotagiftx
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// sets error text definition.
+func init() {
+	appErrorText[SnapshotErrNotFound] = "inventory snapshot not found"
+	appErrorText[SnapshotErrRequiredFields] = "inventory snapshot fields are required"
+}
+
+// Inventory snapshot error types.
+const (
+	SnapshotErrNotFound Errors = iota + snapshotErrorIndex
+	SnapshotErrRequiredFields
+)
+
+// InventorySnapshotKeep is the number of latest snapshots kept per steam id,
+// older ones are pruned when a new snapshot is recorded.
+const InventorySnapshotKeep = 50
+
+// Inventory event types.
+const (
+	// InventoryEventAdded asset appeared on inventory since the previous snapshot.
+	InventoryEventAdded InventoryEventType = "added"
+
+	// InventoryEventRemoved asset left the inventory since the previous snapshot.
+	InventoryEventRemoved InventoryEventType = "removed"
+)
+
+type (
+	// InventorySnapshot represents a compact copy of a steam inventory at the
+	// time of crawl, hash is used to skip storing unchanged inventories.
+	InventorySnapshot struct {
+		ID        string       `json:"id"         db:"id,omitempty"`
+		SteamID   string       `json:"steam_id"   db:"steam_id,omitempty,indexed" valid:"required"`
+		Hash      string       `json:"hash"       db:"hash,omitempty"             valid:"required"`
+		Assets    []SteamAsset `json:"assets"     db:"assets,omitempty"`
+		CreatedAt *time.Time   `json:"created_at" db:"created_at,omitempty,indexed"`
+	}
+
+	// InventoryEventType represents inventory event type.
+	InventoryEventType string
+
+	// InventoryEvent represents an asset change between two snapshots, quantity
+	// of the asset is the number of copies added or removed.
+	InventoryEvent struct {
+		Type    InventoryEventType `json:"type"`
+		SteamID string             `json:"steam_id"`
+		Asset   SteamAsset         `json:"asset"`
+		// Since and Until are the crawl time of the compared snapshots.
+		Since *time.Time `json:"since"`
+		Until *time.Time `json:"until"`
+	}
+
+	// InventorySnapshotService provides access to inventory snapshot service.
+	InventorySnapshotService interface {
+		// Record saves the inventory snapshot when it changed and returns
+		// events since the latest snapshot, events are still returned when
+		// pruning old snapshots fails.
+		Record(ctx context.Context, steamID string, assets []SteamAsset) ([]InventoryEvent, error)
+
+		// Snapshots returns inventory snapshot history of steam id.
+		Snapshots(steamID string, opts FindOpts) ([]InventorySnapshot, error)
+
+		// AppearedSince returns assets that were added to the inventory after
+		// the time given, nil assets when there is no snapshot to compare.
+		AppearedSince(steamID string, since time.Time) ([]SteamAsset, error)
+	}
+
+	// InventorySnapshotStorage defines operation for inventory snapshot records.
+	InventorySnapshotStorage interface {
+		// Find returns a list of snapshots of steam id from data store sorted
+		// by latest.
+		Find(steamID string, opts FindOpts) ([]InventorySnapshot, error)
+
+		// Latest returns the latest snapshot of steam id taken before the time
+		// given from data store.
+		Latest(steamID string, before time.Time) (*InventorySnapshot, error)
+
+		// Create persists a new snapshot to data store.
+		Create(*InventorySnapshot) error
+
+		// Prune deletes snapshots of steam id from data store except the
+		// latest ones to keep.
+		Prune(steamID string, keep int) error
+	}
+)
+
+// NewInventorySnapshot returns a compact snapshot of inventory assets.
+func NewInventorySnapshot(steamID string, assets []SteamAsset) *InventorySnapshot {
+	s := &InventorySnapshot{SteamID: steamID}
+	for _, aa := range assets {
+		s.Assets = append(s.Assets, SteamAsset{
+			AssetID:    aa.AssetID,
+			ClassID:    aa.ClassID,
+			InstanceID: aa.InstanceID,
+			Qty:        max(aa.Qty, 1),
+			Name:       aa.Name,
+			Type:       aa.Type,
+		})
+	}
+	slices.SortFunc(s.Assets, func(a, b SteamAsset) int {
+		if c := cmp.Compare(snapshotKey(a), snapshotKey(b)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.AssetID, b.AssetID)
+	})
+
+	h := sha256.New()
+	for _, aa := range s.Assets {
+		fmt.Fprintf(h, "%s:%d\n", snapshotKey(aa), aa.Qty)
+	}
+	s.Hash = hex.EncodeToString(h.Sum(nil))
+	return s
+}
+
+// CheckCreate validates field on creating new snapshot.
+func (s InventorySnapshot) CheckCreate() error {
+	// Check the required fields.
+	if err := validator.Struct(s); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DiffSnapshots returns added and removed asset events from previous to the
+// next snapshot, assets are compared by class and instance with quantity.
+func DiffSnapshots(prev, next *InventorySnapshot) []InventoryEvent {
+	if next == nil {
+		return nil
+	}
+	if prev == nil {
+		prev = &InventorySnapshot{SteamID: next.SteamID}
+	}
+
+	prevQty := snapshotQty(prev)
+	nextQty := snapshotQty(next)
+	event := func(t InventoryEventType, aa SteamAsset, qty int) InventoryEvent {
+		aa.Qty = qty
+		return InventoryEvent{t, next.SteamID, aa, prev.CreatedAt, next.CreatedAt}
+	}
+
+	var events []InventoryEvent
+	for _, aa := range next.Assets {
+		key := snapshotKey(aa)
+		if d := nextQty[key] - prevQty[key]; d > 0 {
+			events = append(events, event(InventoryEventAdded, aa, d))
+			prevQty[key] = nextQty[key]
+		}
+	}
+	for _, aa := range prev.Assets {
+		key := snapshotKey(aa)
+		if d := prevQty[key] - nextQty[key]; d > 0 {
+			events = append(events, event(InventoryEventRemoved, aa, d))
+			prevQty[key] = nextQty[key]
+		}
+	}
+	return events
+}
+
+// snapshotKey identifies asset across crawls, asset id is not used since
+// it changes when the item is traded or gifted.
+func snapshotKey(a SteamAsset) string {
+	if a.ClassID == "" {
+		return a.AssetID
+	}
+	return a.ClassID + "_" + a.InstanceID
+}
+
+func snapshotQty(s *InventorySnapshot) map[string]int {
+	qty := map[string]int{}
+	for _, aa := range s.Assets {
+		qty[snapshotKey(aa)] += max(aa.Qty, 1)
+	}
+	return qty
+}
+
+// NewInventorySnapshotService returns a new inventory snapshot service.
+func NewInventorySnapshotService(ss InventorySnapshotStorage) InventorySnapshotService {
+	return &inventorySnapshotService{ss}
+}
+
+type inventorySnapshotService struct {
+	snapshotStg InventorySnapshotStorage
+}
+
+func (s *inventorySnapshotService) Record(_ context.Context, steamID string, assets []SteamAsset) ([]InventoryEvent, error) {
+	next := NewInventorySnapshot(steamID, assets)
+	if err := next.CheckCreate(); err != nil {
+		return nil, NewXError(SnapshotErrRequiredFields, err)
+	}
+
+	prev, err := s.snapshotStg.Latest(steamID, time.Now())
+	if err != nil && !errors.Is(err, SnapshotErrNotFound) {
+		return nil, err
+	}
+	if prev != nil && prev.Hash == next.Hash {
+		return nil, nil
+	}
+
+	if err = s.snapshotStg.Create(next); err != nil {
+		return nil, err
+	}
+	events := DiffSnapshots(prev, next)
+	if err = s.snapshotStg.Prune(steamID, InventorySnapshotKeep); err != nil {
+		return events, fmt.Errorf("could not prune snapshots: %w", err)
+	}
+	return events, nil
+}
+
+func (s *inventorySnapshotService) Snapshots(steamID string, opts FindOpts) ([]InventorySnapshot, error) {
+	return s.snapshotStg.Find(steamID, opts)
+}
+
+func (s *inventorySnapshotService) AppearedSince(steamID string, since time.Time) ([]SteamAsset, error) {
+	prev, err := s.snapshotStg.Latest(steamID, since)
+	if errors.Is(err, SnapshotErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	next, err := s.snapshotStg.Latest(steamID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	added := []SteamAsset{}
+	for _, ev := range DiffSnapshots(prev, next) {
+		if ev.Type == InventoryEventAdded {
+			added = append(added, ev.Asset)
+		}
+	}
+	return added, nil
+}
@@ -0,0 +1,129 @@
+package dotagiftx
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	prev := NewInventorySnapshot("1", []SteamAsset{
+		{AssetID: "10", ClassID: "a", InstanceID: "0", Name: "Sylvan Vedette"},
+		{AssetID: "11", ClassID: "b", InstanceID: "0", Name: "Fractal Horns", Qty: 2},
+	})
+	next := NewInventorySnapshot("1", []SteamAsset{
+		{AssetID: "12", ClassID: "b", InstanceID: "0", Name: "Fractal Horns", Qty: 3},
+		{AssetID: "13", ClassID: "c", InstanceID: "0", Name: "Tempest Helm"},
+	})
+
+	got := map[string]InventoryEvent{}
+	for _, ev := range DiffSnapshots(prev, next) {
+		got[string(ev.Type)+" "+ev.Asset.Name] = ev
+	}
+	want := map[string]int{
+		"added Fractal Horns":    1,
+		"added Tempest Helm":     1,
+		"removed Sylvan Vedette": 1,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DiffSnapshots() = %v, want %v", got, want)
+	}
+	for k, qty := range want {
+		if ev, ok := got[k]; !ok || ev.Asset.Qty != qty {
+			t.Errorf("DiffSnapshots() %s = %+v, want qty %d", k, ev, qty)
+		}
+	}
+
+	if DiffSnapshots(next, next) != nil {
+		t.Errorf("DiffSnapshots() same snapshot should have no events")
+	}
+}
+
+func TestNewInventorySnapshot_hash(t *testing.T) {
+	a := []SteamAsset{{AssetID: "1", ClassID: "a"}, {AssetID: "2", ClassID: "b"}}
+	b := []SteamAsset{{AssetID: "2", ClassID: "b"}, {AssetID: "1", ClassID: "a"}}
+	if NewInventorySnapshot("1", a).Hash != NewInventorySnapshot("1", b).Hash {
+		t.Errorf("NewInventorySnapshot() hash should not depend on asset order")
+	}
+	if NewInventorySnapshot("1", a).Hash == NewInventorySnapshot("1", a[:1]).Hash {
+		t.Errorf("NewInventorySnapshot() hash should change on different assets")
+	}
+}
+
+type testSnapshotStorage struct {
+	rows []InventorySnapshot
+}
+
+func (s *testSnapshotStorage) Find(string, FindOpts) ([]InventorySnapshot, error) {
+	return s.rows, nil
+}
+
+func (s *testSnapshotStorage) Latest(_ string, before time.Time) (*InventorySnapshot, error) {
+	for i := len(s.rows) - 1; i >= 0; i-- {
+		if !s.rows[i].CreatedAt.After(before) {
+			return &s.rows[i], nil
+		}
+	}
+	return nil, SnapshotErrNotFound
+}
+
+func (s *testSnapshotStorage) Create(in *InventorySnapshot) error {
+	t := time.Now()
+	in.CreatedAt = &t
+	s.rows = append(s.rows, *in)
+	return nil
+}
+
+func (s *testSnapshotStorage) Prune(_ string, keep int) error {
+	if len(s.rows) > keep {
+		s.rows = s.rows[len(s.rows)-keep:]
+	}
+	return nil
+}
+
+func TestInventorySnapshotService(t *testing.T) {
+	stg := &testSnapshotStorage{}
+	svc := NewInventorySnapshotService(stg)
+	ctx := context.Background()
+	owned := []SteamAsset{{AssetID: "1", ClassID: "a", Name: "Sylvan Vedette"}}
+
+	if _, err := svc.Record(ctx, "1", owned); err != nil {
+		t.Fatal(err)
+	}
+	reservedAt := time.Now()
+	if added, _ := svc.AppearedSince("1", reservedAt.Add(-time.Hour)); added != nil {
+		t.Errorf("AppearedSince() before first snapshot = %v, want nil", added)
+	}
+
+	if events, _ := svc.Record(ctx, "1", owned); events != nil || len(stg.rows) != 1 {
+		t.Errorf("Record() unchanged inventory = %v events %d rows, want skipped", events, len(stg.rows))
+	}
+
+	delivered := append(owned, SteamAsset{AssetID: "2", ClassID: "b", Name: "Tempest Helm"})
+	events, err := svc.Record(ctx, "1", delivered)
+	if err != nil || len(events) != 1 || events[0].Type != InventoryEventAdded {
+		t.Fatalf("Record() = %v, %v, want one added event", events, err)
+	}
+	added, err := svc.AppearedSince("1", reservedAt)
+	if err != nil || len(added) != 1 || added[0].Name != "Tempest Helm" {
+		t.Errorf("AppearedSince() = %v, %v, want Tempest Helm", added, err)
+	}
+}
+
+func TestInventorySnapshotService_prune(t *testing.T) {
+	stg := &testSnapshotStorage{}
+	svc := NewInventorySnapshotService(stg)
+	for i := range InventorySnapshotKeep + 5 {
+		assets := []SteamAsset{{AssetID: fmt.Sprint(i), ClassID: fmt.Sprint(i)}}
+		if _, err := svc.Record(context.Background(), "1", assets); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(stg.rows) != InventorySnapshotKeep {
+		t.Errorf("Record() kept %d snapshots, want %d", len(stg.rows), InventorySnapshotKeep)
+	}
+	if last := stg.rows[len(stg.rows)-1].Assets[0].AssetID; last != fmt.Sprint(InventorySnapshotKeep+4) {
+		t.Errorf("Record() latest snapshot asset = %s, want the last recorded", last)
+	}
+}
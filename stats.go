@@ -15,16 +15,19 @@ type (
 		BidLive      int `json:"bid_live"      db:"bid_live"`
 		BidCompleted int `json:"bid_completed" db:"bid_completed"`
 
-		DeliveryNoHit          int `json:"delivery_no_hit"          db:"delivery_no_hit"`
-		DeliveryNameVerified   int `json:"delivery_name_verified"   db:"delivery_name_verified"`
-		DeliverySenderVerified int `json:"delivery_sender_verified" db:"delivery_sender_verified"`
-		DeliveryPrivate        int `json:"delivery_private"         db:"delivery_private"`
-		DeliveryError          int `json:"delivery_error"           db:"delivery_error"`
-
-		InventoryNoHit    int `json:"inventory_no_hit"   db:"inventory_no_hit"`
-		InventoryVerified int `json:"inventory_verified" db:"inventory_verified"`
-		InventoryPrivate  int `json:"inventory_private"  db:"inventory_private"`
-		InventoryError    int `json:"inventory_error"    db:"inventory_error"`
+		DeliveryNoHit            int `json:"delivery_no_hit"            db:"delivery_no_hit"`
+		DeliveryNameVerified     int `json:"delivery_name_verified"     db:"delivery_name_verified"`
+		DeliverySenderVerified   int `json:"delivery_sender_verified"   db:"delivery_sender_verified"`
+		DeliveryTransferVerified int `json:"delivery_transfer_verified" db:"delivery_transfer_verified"`
+		DeliveryBackdated        int `json:"delivery_backdated"         db:"delivery_backdated"`
+		DeliveryPrivate          int `json:"delivery_private"           db:"delivery_private"`
+		DeliveryError            int `json:"delivery_error"             db:"delivery_error"`
+
+		InventoryNoHit        int `json:"inventory_no_hit"        db:"inventory_no_hit"`
+		InventoryVerified     int `json:"inventory_verified"      db:"inventory_verified"`
+		InventoryOverQuantity int `json:"inventory_over_quantity" db:"inventory_over_quantity"`
+		InventoryPrivate      int `json:"inventory_private"       db:"inventory_private"`
+		InventoryError        int `json:"inventory_error"         db:"inventory_error"`
 
 		ResellLive      int `json:"resell_live" db:"resell_live"`
 		ResellReserved  int `json:"resell_reserved" db:"resell_reserved"`
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 )
 
 type (
@@ -32,21 +33,22 @@ type (
 
 	// SteamAsset represents a simplified version of inventory item.
 	SteamAsset struct {
-		AssetID      string   `json:"asset_id"      db:"asset_id,omitempty"`
-		ClassID      string   `json:"class_id"      db:"class_id,omitempty"` // unique id of an item
-		InstanceID   string   `json:"instance_id"   db:"instance_id,omitempty"`
-		Qty          int      `json:"qty"           db:"qty,omitempty"`
-		Name         string   `json:"name"          db:"name,omitempty"`
-		Image        string   `json:"image"         db:"image,omitempty"`
-		Type         string   `json:"type"          db:"type,omitempty"`
-		Hero         string   `json:"hero"          db:"hero,omitempty"`
-		GiftFrom     string   `json:"gift_from"     db:"gift_from,omitempty"`
-		Contains     string   `json:"contains"      db:"contains,omitempty"`
-		DateReceived string   `json:"date_received" db:"date_received,omitempty"`
-		Dedication   string   `json:"dedication"    db:"dedication,omitempty"`
-		GiftOnce     bool     `json:"gift_once"     db:"gift_once,omitempty"`
-		NotTradable  bool     `json:"not_tradable"  db:"not_tradable,omitempty"`
-		Descriptions []string `json:"descriptions"  db:"descriptions,omitempty"`
+		AssetID      string     `json:"asset_id"      db:"asset_id,omitempty"`
+		ClassID      string     `json:"class_id"      db:"class_id,omitempty"` // unique id of an item
+		InstanceID   string     `json:"instance_id"   db:"instance_id,omitempty"`
+		Qty          int        `json:"qty"           db:"qty,omitempty"`
+		Name         string     `json:"name"          db:"name,omitempty"`
+		Image        string     `json:"image"         db:"image,omitempty"`
+		Type         string     `json:"type"          db:"type,omitempty"`
+		Hero         string     `json:"hero"          db:"hero,omitempty"`
+		GiftFrom     string     `json:"gift_from"     db:"gift_from,omitempty"`
+		Contains     string     `json:"contains"      db:"contains,omitempty"`
+		DateReceived string     `json:"date_received" db:"date_received,omitempty"`
+		ReceivedAt   *time.Time `json:"received_at"   db:"received_at,omitempty"`
+		Dedication   string     `json:"dedication"    db:"dedication,omitempty"`
+		GiftOnce     bool       `json:"gift_once"     db:"gift_once,omitempty"`
+		NotTradable  bool       `json:"not_tradable"  db:"not_tradable,omitempty"`
+		Descriptions []string   `json:"descriptions"  db:"descriptions,omitempty"`
 	}
 )
 
@@ -66,12 +68,6 @@ func (s *SteamAsset) StillWrapped() bool {
 	return strings.ToUpper(s.Type) == "RARE MYSTERIOUS ITEM"
 }
 
-// IsGoldenVariant detects the asset if it's a golden variant and its
-// common pattern that starts with string "GOLDEN"
-func (s *SteamAsset) IsGoldenVariant(name string) bool {
-	return strings.EqualFold(s.Name, fmt.Sprintf("GOLDEN %s", strings.TrimSpace(name)))
-}
-
 // IsBundledVariant detects the asset if it's a bundle variant and its
 // common pattern that ends with string "GOLDEN"
 func (s *SteamAsset) IsBundledVariant(name string) bool {
@@ -1,22 +1,23 @@
 package steam
 
 import (
+	"context"
 	"errors"
-	"io"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
 )
 
-var (
-	steamLogin = "https://steamcommunity.com/openid/login"
+const steamLoginPath = "/openid/login"
 
+var (
 	openidMode       = "checkid_setup"
 	openidNs         = "http://specs.openid.net/auth/2.0"
 	openidIdentifier = "http://specs.openid.net/auth/2.0/identifier_select"
 
 	validationRegexp       = regexp.MustCompile(`^(http|https)://steamcommunity\.com/openid/id/[0-9]{15,25}$`)
+	claimedIDPathRegexp    = regexp.MustCompile(`^/openid/id/[0-9]{15,25}$`)
 	digitsExtractionRegexp = regexp.MustCompile(`\D+`)
 )
 
@@ -24,10 +25,16 @@ type OpenId struct {
 	root      string
 	returnUrl string
 	data      url.Values
+
+	ctx    context.Context
+	client *Client
 }
 
+// NewOpenId returns open id handler of the request that uses the default client.
 func NewOpenId(r *http.Request, cfg Config) *OpenId {
 	id := new(OpenId)
+	id.ctx = r.Context()
+	id.client = defaultClient
 
 	proto := "http://"
 	if r.TLS != nil {
@@ -68,7 +75,7 @@ func (id OpenId) AuthUrl() string {
 	}
 
 	i := 0
-	u := steamLogin + "?"
+	u := id.client.config.CommunityURL + steamLoginPath + "?"
 	for key, value := range data {
 		u += key + "=" + value
 		if i != len(data)-1 {
@@ -100,12 +107,12 @@ func (id *OpenId) ValidateAndGetId() (string, error) {
 	}
 	params.Set("openid.mode", "check_authentication")
 
-	resp, err := http.PostForm(steamLogin, params)
-	if err != nil {
+	// Assertion nonce can only be checked once and should not be retried.
+	if err := id.client.limiter.wait(id.ctx); err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-	content, err := io.ReadAll(resp.Body)
+	loginURL := id.client.config.CommunityURL + steamLoginPath
+	content, _, err := id.client.send(id.ctx, http.MethodPost, loginURL, strings.NewReader(params.Encode()), maxResponseBytes)
 	if err != nil {
 		return "", err
 	}
@@ -119,11 +126,13 @@ func (id *OpenId) ValidateAndGetId() (string, error) {
 	}
 
 	openIdUrl := id.data.Get("openid.claimed_id")
-	if !validationRegexp.MatchString(openIdUrl) {
+	if !id.isValidClaimedID(openIdUrl) {
 		return "", errors.New("invalid steam id patterns")
 	}
 
-	return digitsExtractionRegexp.ReplaceAllString(openIdUrl, ""), nil
+	// Steam id is on the last path segment since host might contain digits.
+	steamID := openIdUrl[strings.LastIndex(openIdUrl, "/")+1:]
+	return digitsExtractionRegexp.ReplaceAllString(steamID, ""), nil
 }
 
 func (id OpenId) ValidateAndGetUser(apiKey string) (*PlayerSummaries, error) {
@@ -131,7 +140,19 @@ func (id OpenId) ValidateAndGetUser(apiKey string) (*PlayerSummaries, error) {
 	if err != nil {
 		return nil, err
 	}
-	return GetPlayerSummaries(steamId, apiKey)
+	return id.client.playerSummaries(id.ctx, steamId, apiKey)
+}
+
+// isValidClaimedID checks claimed id pattern, claimed id of an overridden
+// community host should be on the same host.
+func (id OpenId) isValidClaimedID(claimedID string) bool {
+	community := id.client.config.CommunityURL
+	if community == defaultCommunityURL {
+		return validationRegexp.MatchString(claimedID)
+	}
+
+	path, ok := strings.CutPrefix(claimedID, community)
+	return ok && claimedIDPathRegexp.MatchString(path)
 }
 
 func (id OpenId) Mode() string {
@@ -0,0 +1,207 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRateLimit  = time.Second
+	defaultMaxRetries = 3
+	defaultTimeout    = time.Second * 30
+
+	maxBackoff = time.Minute
+
+	// maxResponseBytes limits web api response body.
+	maxResponseBytes = 1 << 20
+)
+
+// Steam request errors.
+var (
+	ErrRateLimited = errors.New("steam rate limited")
+	ErrNotFound    = errors.New("steam resource not found")
+	ErrMalformed   = errors.New("steam response malformed")
+)
+
+// retryBackoff is the base wait before retrying without a Retry-After header.
+var retryBackoff = time.Second * 2
+
+// RateLimitError represents too many requests response, RetryAfter is the
+// wait suggested by steam when available.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter == 0 {
+		return ErrRateLimited.Error()
+	}
+	return fmt.Sprintf("%s: retry after %s", ErrRateLimited, e.RetryAfter)
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// StatusError represents unexpected response status code.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("steam response status %d", e.Code)
+}
+
+// limiter spaces out requests of a client to steam.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newLimiter(interval time.Duration) *limiter {
+	return &limiter{interval: interval}
+}
+
+// wait blocks until the next request slot or context is done.
+func (l *limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	return sleep(ctx, slot.Sub(now))
+}
+
+// pause holds off all requests until the time given.
+func (l *limiter) pause(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.next) {
+		l.next = until
+	}
+}
+
+// get sends rate limited request and retries on rate limit and server
+// errors, the response body is returned on success.
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	return c.getLimit(ctx, url, maxResponseBytes)
+}
+
+// getLimit is get with response body limit, ErrInventoryTooLarge is
+// returned when the body exceeds it.
+func (c *Client) getLimit(ctx context.Context, url string, limit int64) ([]byte, error) {
+	var lastErr error
+	retries := max(c.config.MaxRetries, 0)
+	for attempt := 0; attempt <= retries; attempt++ {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		body, retryAfter, err := c.send(ctx, http.MethodGet, url, nil, limit)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+
+		var rle *RateLimitError
+		var se *StatusError
+		switch {
+		case errors.As(err, &rle):
+		case errors.As(err, &se) && se.Code >= http.StatusInternalServerError:
+		default:
+			return nil, err
+		}
+
+		// Backs off exponentially when steam did not suggest.
+		wait := retryAfter
+		if wait < 0 {
+			wait = min(retryBackoff<<attempt, maxBackoff)
+		}
+		if rle != nil {
+			c.limiter.pause(time.Now().Add(wait))
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return nil, err
+		}
+		if err = sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// send returns response body and Retry-After value, negative when not set.
+func (c *Client) send(ctx context.Context, method, url string, body io.Reader, limit int64) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, -1, err
+	}
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, -1, err
+	}
+	defer res.Body.Close()
+
+	retryAfter := parseRetryAfter(res.Header.Get("Retry-After"))
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		return nil, retryAfter, &RateLimitError{RetryAfter: max(retryAfter, 0)}
+	case res.StatusCode == http.StatusNotFound:
+		return nil, retryAfter, ErrNotFound
+	case res.StatusCode >= http.StatusBadRequest:
+		return nil, retryAfter, &StatusError{res.StatusCode}
+	}
+
+	b, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+	if err != nil {
+		return nil, retryAfter, err
+	}
+	if int64(len(b)) > limit {
+		return nil, retryAfter, fmt.Errorf("%w: response over %d bytes", ErrInventoryTooLarge, limit)
+	}
+	return b, retryAfter, nil
+}
+
+// parseRetryAfter supports delay seconds and http date values.
+func parseRetryAfter(s string) time.Duration {
+	if s == "" {
+		return -1
+	}
+	if sec, err := strconv.Atoi(s); err == nil {
+		return time.Duration(max(sec, 0)) * time.Second
+	}
+	if t, err := http.ParseTime(s); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return -1
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,116 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_parseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", -1},
+		{"0", 0},
+		{"5", time.Second * 5},
+		{"-3", 0},
+		{"soon", -1},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestClient_get(t *testing.T) {
+	retryBackoff = time.Millisecond
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		switch r.URL.Path {
+		case "/flaky":
+			if n < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte(`{}`))
+		case "/limited":
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/bad":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	c, _ := New(Config{RateLimit: time.Millisecond, MaxRetries: 3}, nil, nil)
+
+	tests := []struct {
+		path      string
+		wantErr   error
+		wantCalls int32
+	}{
+		{"/flaky", nil, 3},
+		{"/limited", ErrRateLimited, 4},
+		{"/missing", ErrNotFound, 1},
+	}
+	for _, tt := range tests {
+		calls.Store(0)
+		_, err := c.get(context.Background(), srv.URL+tt.path)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("get(%s) error = %v, want %v", tt.path, err, tt.wantErr)
+		}
+		if calls.Load() != tt.wantCalls {
+			t.Errorf("get(%s) calls = %d, want %d", tt.path, calls.Load(), tt.wantCalls)
+		}
+	}
+
+	var se *StatusError
+	if _, err := c.get(context.Background(), srv.URL+"/bad"); !errors.As(err, &se) || se.Code != http.StatusBadRequest {
+		t.Errorf("get(/bad) error = %v, want status error 400", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.get(ctx, srv.URL+"/flaky"); !errors.Is(err, context.Canceled) {
+		t.Errorf("get() cancelled error = %v, want %v", err, context.Canceled)
+	}
+
+	calls.Store(0)
+	noRetry, _ := New(Config{RateLimit: time.Millisecond, MaxRetries: -1}, nil, nil)
+	if _, err := noRetry.get(context.Background(), srv.URL+"/limited"); !errors.Is(err, ErrRateLimited) || calls.Load() != 1 {
+		t.Errorf("get() retries disabled error = %v calls = %d, want %v with 1 call", err, calls.Load(), ErrRateLimited)
+	}
+}
+
+func TestNew_clientScoped(t *testing.T) {
+	def := defaultClient
+	c, err := New(Config{CommunityURL: "http://localhost:8080/", APIURL: "http://localhost:8081", RateLimit: time.Millisecond}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.config.CommunityURL; got != "http://localhost:8080" {
+		t.Errorf("New() community url = %s, want http://localhost:8080", got)
+	}
+	if got := c.config.APIURL; got != "http://localhost:8081" {
+		t.Errorf("New() api url = %s, want http://localhost:8081", got)
+	}
+	if defaultClient != def || defaultClient.config.CommunityURL != defaultCommunityURL ||
+		defaultClient.limiter.interval != defaultRateLimit {
+		t.Errorf("New() should not change the default client")
+	}
+
+	SetDefault(c)
+	defer SetDefault(def)
+	if defaultClient != c {
+		t.Errorf("SetDefault() default client = %p, want %p", defaultClient, c)
+	}
+}
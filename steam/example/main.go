@@ -10,7 +10,7 @@ import (
 var client *steam.Client
 
 func main() {
-	c, err := steam.New(steam.Config{Key: "STEAM_WEB_API_KEY"}, nil)
+	c, err := steam.New(steam.Config{Key: "STEAM_WEB_API_KEY"}, nil, nil)
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -1,12 +1,14 @@
 package steam
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/kudarap/dotagiftx"
@@ -23,21 +25,73 @@ var (
 // Asset represents a compact inventory base of RawInventory model.
 type Asset = dotagiftx.SteamAsset
 
-// InventoryAsset returns a compact format from raw inventory data.
+// InventoryAsset returns a compact format from raw inventory data using the default client.
 func InventoryAsset(ctx context.Context, steamID string) ([]Asset, error) {
-	r, err := reqDota2Inventory(steamID)
+	return defaultClient.InventoryAsset(ctx, steamID)
+}
+
+func InventoryAssetWithProvider(ctx context.Context, steamID string) (string, []Asset, error) {
+	return defaultClient.InventoryAssetWithProvider(ctx, steamID)
+}
+
+// InventoryAsset returns a compact format from all inventory pages.
+func (c *Client) InventoryAsset(ctx context.Context, steamID string) ([]Asset, error) {
+	all, err := c.AllInventory(ctx, steamID)
 	if err != nil {
-		return nil, fmt.Errorf("could send request: %s", err)
+		return nil, err
 	}
-	defer r.Body.Close()
-	return assetParser(r.Body)
+	return all.ToAssets(), nil
 }
 
-func InventoryAssetWithProvider(ctx context.Context, steamID string) (string, []Asset, error) {
-	res, err := InventoryAsset(ctx, steamID)
+func (c *Client) InventoryAssetWithProvider(ctx context.Context, steamID string) (string, []Asset, error) {
+	res, err := c.InventoryAsset(ctx, steamID)
 	return providerID, res, err
 }
 
+func init() {
+	dotagiftx.RegisterInventoryProviderFactory(providerID, newProvider)
+}
+
+// newProvider creates steam inventory provider from steam config and cache on
+// the setup, client on the setup is shared as is since steam rate limits by
+// IP address.
+func newProvider(s dotagiftx.InventoryProviderSetup) (dotagiftx.InventoryProvider, error) {
+	if c, ok := dotagiftx.SetupValue[*Client](s); ok {
+		return c, nil
+	}
+	c, _ := dotagiftx.SetupValue[Config](s)
+	ca, _ := dotagiftx.SetupValue[cacheReadWriter](s)
+	return New(c, ca, nil)
+}
+
+// Name returns the inventory provider id.
+func (c *Client) Name() string { return providerID }
+
+// Capabilities returns live inventory directly from steam.
+func (c *Client) Capabilities() dotagiftx.ProviderCapabilities {
+	return dotagiftx.ProviderCapabilities{Private: true}
+}
+
+// Invalidate does nothing since inventory is not cached.
+func (c *Client) Invalidate(context.Context, string) error { return nil }
+
+// Health reports healthy since steam rate limits are tracked per request.
+func (c *Client) Health(context.Context) error { return nil }
+
+// ParseInventory returns a compact format from a captured raw inventory data.
+func ParseInventory(r io.Reader) ([]Asset, error) {
+	return assetParser(r)
+}
+
+// ParseAllInventory returns a compact format from a captured collated inventory data.
+func ParseAllInventory(r io.Reader) ([]Asset, error) {
+	var all AllInventory
+	if err := fastjson.NewDecoder(r).Decode(&all); err != nil {
+		return nil, err
+	}
+	return all.ToAssets(), nil
+}
+
 func assetParser(r io.Reader) ([]Asset, error) {
 	raw, err := inventoryParser(r)
 	if err != nil {
@@ -140,14 +194,35 @@ func (i *RawInventory) ToAssets() []Asset {
 	return assets
 }
 
-// Inventory retrieve data from API and parse into RawInventory.
+// Inventory retrieve data from API and parse into RawInventory using the default client.
 func Inventory(steamID string) (*RawInventory, error) {
-	r, err := reqDota2Inventory(steamID)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	return defaultClient.Inventory(ctx, steamID)
+}
+
+// Inventory retrieve data from API and parse into RawInventory, only the
+// first page is returned and AllInventory should be used for large inventory.
+func (c *Client) Inventory(ctx context.Context, steamID string) (*RawInventory, error) {
+	body, err := c.inventory(ctx, steamID)
+	if err != nil {
+		return nil, err
+	}
+	return inventoryParser(bytes.NewReader(body))
+}
+
+func (c *Client) inventory(ctx context.Context, steamID string) ([]byte, error) {
+	url := fmt.Sprintf(inventoryEndpoint, c.config.CommunityURL, steamID, Dota2AppID)
+	body, err := c.get(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("could send request: %s", err)
+		// Steam responds forbidden on private inventory.
+		var se *StatusError
+		if errors.As(err, &se) && se.Code == http.StatusForbidden {
+			return nil, ErrInventoryPrivate
+		}
+		return nil, err
 	}
-	defer r.Body.Close()
-	return inventoryParser(r.Body)
+	return body, nil
 }
 
 func inventoryParser(r io.Reader) (*RawInventory, error) {
@@ -157,7 +232,7 @@ func inventoryParser(r io.Reader) (*RawInventory, error) {
 		return nil, err
 	}
 	if err = fastjson.Unmarshal(b, raw); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
 	}
 	return raw, nil
 }
@@ -179,6 +254,9 @@ const (
 	assetPrefixContains     = "Contains: "
 	assetFlagNotTradable    = "( Not Tradable )"
 	assetFlagGiftOnce       = "( This item may be gifted once )"
+
+	// assetDateReceivedLayout date received value format eg. "Aug 24, 2020 (23:15:11)".
+	assetDateReceivedLayout = "Jan 2, 2006 (15:04:05)"
 )
 
 // RawInventoryDesc represents steam's raw description inventory data model.
@@ -211,6 +289,7 @@ func (d RawInventoryDesc) ToAsset() Asset {
 		}
 		if pv, ok := extractValueFromPrefix(dd.Value, assetPrefixDateReceived); ok {
 			asset.DateReceived = pv
+			asset.ReceivedAt = parseDateReceived(pv)
 		}
 		if pv, ok := extractValueFromPrefix(dd.Value, assetPrefixDedication); ok {
 			asset.Dedication = pv
@@ -275,12 +354,7 @@ func (po *RawInventoryPageOffset) UnmarshalJSON(data []byte) error {
 }
 
 const Dota2AppID = 570
-const inventoryEndpoint = "https://steamcommunity.com/profiles/%s/inventory/json/%d/2"
-
-func reqDota2Inventory(steamID string) (*http.Response, error) {
-	url := fmt.Sprintf(inventoryEndpoint, steamID, Dota2AppID)
-	return http.Get(url)
-}
+const inventoryEndpoint = "%s/profiles/%s/inventory/json/%d/2"
 
 func extractValueFromPrefix(s, prefix string) (value string, ok bool) {
 	if !strings.HasPrefix(strings.ToUpper(s), strings.ToUpper(prefix)) {
@@ -290,6 +364,15 @@ func extractValueFromPrefix(s, prefix string) (value string, ok bool) {
 	return strings.TrimPrefix(s, prefix), true
 }
 
+// parseDateReceived returns nil when date received value is not recognized.
+func parseDateReceived(s string) *time.Time {
+	t, err := time.Parse(assetDateReceivedLayout, strings.TrimSpace(s))
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
 func isFlagExists(s, flag string) (ok bool) {
 	return strings.EqualFold(s, flag)
 }
@@ -0,0 +1,94 @@
+package steam
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	defaultInventoryMaxPages = 20
+	defaultInventoryMaxBytes = 64 << 20
+
+	// inventoryPageCount is the max assets per page allowed by steam.
+	inventoryPageCount    = 2000
+	inventoryPageEndpoint = "%s/inventory/%s/%d/2?count=%d&start_assetid=%s"
+)
+
+// ErrInventoryTooLarge inventory exceeds the page or size limit, partial
+// inventory is not returned since it might miss the item being verified.
+var ErrInventoryTooLarge = errors.New("steam inventory too large")
+
+// InventoryPage represents steam's paginated inventory data model.
+type InventoryPage struct {
+	Assets              []RawInventoryAsset `json:"assets"`
+	Descriptions        []RawInventoryDesc  `json:"descriptions"`
+	TotalInventoryCount int                 `json:"total_inventory_count"`
+	LastAssetID         string              `json:"last_assetid"`
+	MoreItems           int                 `json:"more_items"`
+	Success             int                 `json:"success"`
+}
+
+// AllInventory walks all inventory pages using last asset id cursor and
+// returns the merged inventory.
+func (c *Client) AllInventory(ctx context.Context, steamID string) (*AllInventory, error) {
+	all := &AllInventory{AllDescs: map[string]RawInventoryDesc{}}
+	budget := c.config.InventoryMaxBytes
+	var cursor string
+	for pages := 1; ; pages++ {
+		if pages > c.config.InventoryMaxPages {
+			return nil, fmt.Errorf("%w: more than %d pages", ErrInventoryTooLarge, c.config.InventoryMaxPages)
+		}
+
+		url := fmt.Sprintf(inventoryPageEndpoint, c.config.CommunityURL, steamID, Dota2AppID, inventoryPageCount, cursor)
+		body, err := c.getLimit(ctx, url, budget)
+		if err != nil {
+			var se *StatusError
+			if errors.As(err, &se) && se.Code == http.StatusForbidden {
+				return nil, ErrInventoryPrivate
+			}
+			return nil, err
+		}
+		budget -= int64(len(body))
+
+		page, err := inventoryPageParser(body)
+		if err != nil {
+			return nil, err
+		}
+		all.merge(page)
+		if page.MoreItems == 0 || page.LastAssetID == "" {
+			return all, nil
+		}
+		cursor = page.LastAssetID
+	}
+}
+
+func inventoryPageParser(b []byte) (*InventoryPage, error) {
+	// Steam responds null on unknown profiles.
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		return nil, ErrNotFound
+	}
+
+	page := &InventoryPage{}
+	if err := fastjson.Unmarshal(b, page); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+	if page.Success != 1 {
+		return nil, fmt.Errorf("%w: success %d", ErrMalformed, page.Success)
+	}
+	return page, nil
+}
+
+// merge appends page assets and its descriptions that are not yet collected.
+func (i *AllInventory) merge(page *InventoryPage) {
+	i.AllInvs = append(i.AllInvs, page.Assets...)
+	for _, d := range page.Descriptions {
+		key := d.ClassID + "_" + d.InstanceID
+		if _, ok := i.AllDescs[key]; ok {
+			continue
+		}
+		i.AllDescs[key] = d
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 var testAssetData = map[string]RawInventoryAsset{
@@ -46,6 +47,8 @@ var descGothicWhisperUnWrapped = RawInventoryDesc{
 	},
 }
 
+var receivedGothicWhisper = time.Date(2020, 8, 24, 23, 15, 11, 0, time.UTC)
+
 var assetGothicWhisper = Asset{
 	AssetID:      "100000000",
 	ClassID:      "3305750400",
@@ -57,6 +60,7 @@ var assetGothicWhisper = Asset{
 	Hero:         "Phantom Assassin",
 	GiftFrom:     "gippeum",
 	DateReceived: "Aug 24, 2020 (23:15:11)",
+	ReceivedAt:   &receivedGothicWhisper,
 	Descriptions: []string{
 		"Used By: Phantom Assassin",
 		"The International 2019",
@@ -1,15 +1,16 @@
 package steam
 
 import (
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 
 	jsoniter "github.com/json-iterator/go"
 )
 
 var json = jsoniter.ConfigCompatibleWithStandardLibrary
 
+const vanityNoMatch = 42
+
 type PlayerSummaries struct {
 	SteamId                  string `json:"steamid"`
 	CommunityVisibilityState int    `json:"communityvisibilitystate"`
@@ -25,13 +26,21 @@ type PlayerSummaries struct {
 	RealName                 string `json:"realname"`
 }
 
+// GetPlayerSummaries returns player summaries using the default client.
 func GetPlayerSummaries(steamId, apiKey string) (*PlayerSummaries, error) {
-	url := fmt.Sprintf("https://api.steampowered.com/ISteamUser/GetPlayerSummaries/v0002/?key=%s&steamids=%s", apiKey, steamId)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	body, err := io.ReadAll(resp.Body)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	return defaultClient.playerSummaries(ctx, steamId, apiKey)
+}
+
+// PlayerSummaries returns player summaries of steam id.
+func (c *Client) PlayerSummaries(ctx context.Context, steamID string) (*PlayerSummaries, error) {
+	return c.playerSummaries(ctx, steamID, c.config.Key)
+}
+
+func (c *Client) playerSummaries(ctx context.Context, steamId, apiKey string) (*PlayerSummaries, error) {
+	url := fmt.Sprintf("%s/ISteamUser/GetPlayerSummaries/v0002/?key=%s&steamids=%s", c.config.APIURL, apiKey, steamId)
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -43,23 +52,31 @@ func GetPlayerSummaries(steamId, apiKey string) (*PlayerSummaries, error) {
 	}
 	var data Result
 	if err := json.Unmarshal(body, &data); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
 	}
 
 	if len(data.Response.Players) == 0 {
-		return nil, fmt.Errorf("no result")
+		return nil, fmt.Errorf("%w: no player result", ErrNotFound)
 	}
 
 	return &data.Response.Players[0], err
 }
 
+// ResolveVanityURL returns steam id of vanity url name using the default client.
 func ResolveVanityURL(vanityURL, apiKey string) (steamID string, err error) {
-	url := fmt.Sprintf("https://api.steampowered.com/ISteamUser/ResolveVanityURL/v1/?key=%s&vanityurl=%s", apiKey, vanityURL)
-	resp, err := http.Get(url)
-	if err != nil {
-		return
-	}
-	body, err := io.ReadAll(resp.Body)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	return defaultClient.resolveVanity(ctx, vanityURL, apiKey)
+}
+
+// ResolveVanity returns steam id of vanity url name.
+func (c *Client) ResolveVanity(ctx context.Context, vanityURL string) (steamID string, err error) {
+	return c.resolveVanity(ctx, vanityURL, c.config.Key)
+}
+
+func (c *Client) resolveVanity(ctx context.Context, vanityURL, apiKey string) (steamID string, err error) {
+	url := fmt.Sprintf("%s/ISteamUser/ResolveVanityURL/v1/?key=%s&vanityurl=%s", c.config.APIURL, apiKey, vanityURL)
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return
 	}
@@ -72,9 +89,14 @@ func ResolveVanityURL(vanityURL, apiKey string) (steamID string, err error) {
 	}
 	var data Result
 	if err = json.Unmarshal(body, &data); err != nil {
+		err = fmt.Errorf("%w: %s", ErrMalformed, err)
 		return
 	}
 
+	if data.Response.Success == vanityNoMatch {
+		err = fmt.Errorf("%w: steam resolve vanity url no match", ErrNotFound)
+		return
+	}
 	if data.Response.Success != 1 {
 		err = fmt.Errorf("steam resolve vanity url error %d", data.Response.Success)
 		return
@@ -1,6 +1,7 @@
 package steam
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -14,6 +15,9 @@ const (
 	VanityPrefixProfile = "https://steamcommunity.com/profiles/"
 
 	vanityCacheExpr = time.Hour * 24
+
+	defaultCommunityURL = "https://steamcommunity.com"
+	defaultAPIURL       = "https://api.steampowered.com"
 )
 
 // Config represents steam config.
@@ -21,21 +25,80 @@ type Config struct {
 	Key    string
 	Realm  string
 	Return string
+	// CommunityURL and APIURL overrides steam hosts, defaults to the real steam hosts when empty.
+	CommunityURL string `envconfig:"COMMUNITY_URL"`
+	APIURL       string `envconfig:"API_URL"`
+	// RateLimit is the minimum interval between requests of the client.
+	RateLimit time.Duration `envconfig:"RATE_LIMIT"`
+	// MaxRetries of rate limited and server error responses, -1 disables retries.
+	MaxRetries int `envconfig:"MAX_RETRIES"`
+	// InventoryMaxPages and InventoryMaxBytes caps inventory pagination to protect memory.
+	InventoryMaxPages int   `envconfig:"INVENTORY_MAX_PAGES"`
+	InventoryMaxBytes int64 `envconfig:"INVENTORY_MAX_BYTES"`
+}
+
+func (c Config) setDefault() Config {
+	c.CommunityURL = strings.TrimRight(c.CommunityURL, "/")
+	if c.CommunityURL == "" {
+		c.CommunityURL = defaultCommunityURL
+	}
+	c.APIURL = strings.TrimRight(c.APIURL, "/")
+	if c.APIURL == "" {
+		c.APIURL = defaultAPIURL
+	}
+	if c.RateLimit <= 0 {
+		c.RateLimit = defaultRateLimit
+	}
+	if c.MaxRetries == 0 {
+		c.MaxRetries = defaultMaxRetries
+	}
+	if c.InventoryMaxPages <= 0 {
+		c.InventoryMaxPages = defaultInventoryMaxPages
+	}
+	if c.InventoryMaxBytes <= 0 {
+		c.InventoryMaxBytes = defaultInventoryMaxBytes
+	}
+	return c
 }
 
 // Client represents a steam client.
 type Client struct {
-	config Config
-	cache  cacheReadWriter
+	config  Config
+	cache   cacheReadWriter
+	http    *http.Client
+	limiter *limiter
 }
 
-// New create new steam client instance.
-func New(c Config, ca cacheReadWriter) (*Client, error) {
-	return &Client{c, ca}, nil
+// New create new steam client instance, http client with default timeout
+// will be used when hc is nil.
+func New(c Config, ca cacheReadWriter, hc *http.Client) (*Client, error) {
+	c = c.setDefault()
+	if hc == nil {
+		hc = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Client{c, ca, hc, newLimiter(c.RateLimit)}, nil
 }
 
-func (c *Client) AuthorizeURL(r *http.Request) (redirectURL string, err error) {
+// openID returns open id handler of the request using the client.
+func (c *Client) openID(r *http.Request) *OpenId {
 	oid := NewOpenId(r, c.config)
+	oid.client = c
+	return oid
+}
+
+// defaultClient serves the package level requests.
+var defaultClient = &Client{Config{}.setDefault(), nil, &http.Client{Timeout: defaultTimeout}, newLimiter(defaultRateLimit)}
+
+// SetDefault sets the client that serves the package level requests, steam
+// rate limits by IP address so the package level requests should share the
+// limiter and hosts of the configured client. It should be called on setup
+// before any package level requests.
+func SetDefault(c *Client) {
+	defaultClient = c
+}
+
+func (c *Client) AuthorizeURL(r *http.Request) (redirectURL string, err error) {
+	oid := c.openID(r)
 	if oid.Mode() != "" {
 		err = fmt.Errorf("could not get redirect URL: %s", oid.Mode())
 		return
@@ -45,7 +108,7 @@ func (c *Client) AuthorizeURL(r *http.Request) (redirectURL string, err error) {
 }
 
 func (c *Client) Authenticate(r *http.Request) (*dotagiftx.SteamPlayer, error) {
-	oid := NewOpenId(r, c.config)
+	oid := c.openID(r)
 	m := oid.Mode()
 	if m == "cancel" {
 		return nil, fmt.Errorf("authorization cancelled")
@@ -56,7 +119,7 @@ func (c *Client) Authenticate(r *http.Request) (*dotagiftx.SteamPlayer, error) {
 		return nil, fmt.Errorf("could not validate player: %s", err)
 	}
 
-	p, err := c.Player(id)
+	p, err := c.player(r.Context(), id)
 	if err != nil {
 		return nil, fmt.Errorf("could not get player: %s", err)
 	}
@@ -64,9 +127,15 @@ func (c *Client) Authenticate(r *http.Request) (*dotagiftx.SteamPlayer, error) {
 }
 
 func (c *Client) Player(steamID string) (*dotagiftx.SteamPlayer, error) {
-	su, err := GetPlayerSummaries(steamID, c.config.Key)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	return c.player(ctx, steamID)
+}
+
+func (c *Client) player(ctx context.Context, steamID string) (*dotagiftx.SteamPlayer, error) {
+	su, err := c.PlayerSummaries(ctx, steamID)
 	if err != nil {
-		return nil, fmt.Errorf("could not get player: %s", err)
+		return nil, fmt.Errorf("could not get player: %w", err)
 	}
 
 	return &dotagiftx.SteamPlayer{
@@ -91,7 +160,9 @@ func (c *Client) ResolveVanityURL(rawURL string) (steamID string, err error) {
 		return strings.ReplaceAll(hit, `"`, ""), nil
 	}
 
-	steamID, err = ResolveVanityURL(vanity, c.config.Key)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	steamID, err = c.ResolveVanity(ctx, vanity)
 	if err != nil {
 		return
 	}
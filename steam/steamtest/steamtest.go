@@ -0,0 +1,436 @@
+// Package steamtest provides a fake steam community and web api server that
+// serves inventories, player summaries, vanity resolution and openid login
+// from fixtures, so the login and verification path can run offline.
+//
+// Usage:
+//
+//	srv := steamtest.NewServer()
+//	defer srv.Close()
+//	client, _ := steam.New(srv.Config(), cache, nil)
+package steamtest
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kudarap/dotagiftx/steam"
+)
+
+const (
+	// Key is the web api key accepted by the fake server.
+	Key = "steamtest"
+
+	// PrivateError is the inventory error text of private profiles.
+	PrivateError = "This profile is private."
+
+	openidNs     = "http://specs.openid.net/auth/2.0"
+	openidSigned = "signed,op_endpoint,claimed_id,identity,return_to,response_nonce,assoc_handle"
+)
+
+// Server represents a fake steam server.
+type Server struct {
+	*httptest.Server
+
+	// PageSize splits inventory responses into pages of assets, zero value
+	// serves the whole inventory at once.
+	PageSize int
+
+	mu          sync.Mutex
+	signKey     []byte
+	inventories map[string]*steam.RawInventory
+	statuses    map[string]int
+	limited     map[string]int
+	players     map[string]steam.PlayerSummaries
+	vanities    map[string]string
+	loginID     string
+}
+
+// NewServer starts and returns a new fake steam server, caller should call
+// Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		signKey:     []byte(rand.Text()),
+		inventories: map[string]*steam.RawInventory{},
+		statuses:    map[string]int{},
+		limited:     map[string]int{},
+		players:     map[string]steam.PlayerSummaries{},
+		vanities:    map[string]string{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /profiles/{id}/inventory/json/{app}/{context}", s.handleInventory)
+	mux.HandleFunc("GET /inventory/{id}/{app}/{context}", s.handleInventoryPage)
+	mux.HandleFunc("GET /ISteamUser/GetPlayerSummaries/v0002/", s.handlePlayerSummaries)
+	mux.HandleFunc("GET /ISteamUser/ResolveVanityURL/v1/", s.handleResolveVanityURL)
+	mux.HandleFunc("GET /openid/login", s.handleLogin)
+	mux.HandleFunc("POST /openid/login", s.handleCheckAuthentication)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Config returns steam client config that points to the fake server
+// without waiting between requests.
+func (s *Server) Config() steam.Config {
+	return steam.Config{
+		Key:          Key,
+		CommunityURL: s.URL,
+		APIURL:       s.URL,
+		RateLimit:    time.Millisecond,
+	}
+}
+
+// SetInventory sets raw inventory response of steam id.
+func (s *Server) SetInventory(steamID string, inv *steam.RawInventory) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inventories[steamID] = inv
+	delete(s.statuses, steamID)
+}
+
+// LoadInventory sets raw inventory response of steam id from a fixture file.
+func (s *Server) LoadInventory(steamID, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var inv steam.RawInventory
+	if err = json.Unmarshal(b, &inv); err != nil {
+		return fmt.Errorf("unmarshal %s: %s", path, err)
+	}
+	s.SetInventory(steamID, &inv)
+	return nil
+}
+
+// SetPrivate sets private inventory response of steam id.
+func (s *Server) SetPrivate(steamID string) {
+	s.SetInventory(steamID, &steam.RawInventory{Error: PrivateError})
+}
+
+// SetInventoryStatus sets an error status code response of steam id
+// inventory, eg. http.StatusTooManyRequests for rate limits.
+func (s *Server) SetInventoryStatus(steamID string, code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.statuses[steamID] = code
+}
+
+// SetRateLimited responds too many requests on the next inventory requests
+// of steam id with Retry-After header of zero seconds.
+func (s *Server) SetRateLimited(steamID string, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.limited[steamID] = times
+}
+
+// SetPlayer sets player summaries by its steam id.
+func (s *Server) SetPlayer(p steam.PlayerSummaries) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.players[p.SteamId] = p
+}
+
+// SetVanity sets vanity url name that resolves to steam id.
+func (s *Server) SetVanity(vanity, steamID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.vanities[vanity] = steamID
+}
+
+// LoginAs sets the steam id that signs in on openid login, empty value
+// cancels the login.
+func (s *Server) LoginAs(steamID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.loginID = steamID
+}
+
+// Assertion returns signed openid positive assertion values of steam id
+// that steam sends to the return url after login.
+func (s *Server) Assertion(returnTo, steamID string) url.Values {
+	claimedID := s.URL + "/openid/id/" + steamID
+	v := url.Values{}
+	v.Set("openid.ns", openidNs)
+	v.Set("openid.mode", "id_res")
+	v.Set("openid.op_endpoint", s.URL+"/openid/login")
+	v.Set("openid.claimed_id", claimedID)
+	v.Set("openid.identity", claimedID)
+	v.Set("openid.return_to", returnTo)
+	v.Set("openid.response_nonce", time.Now().UTC().Format(time.RFC3339)+rand.Text()[:8])
+	v.Set("openid.assoc_handle", "1234567890")
+	v.Set("openid.signed", openidSigned)
+	v.Set("openid.sig", s.sign(v))
+	return v
+}
+
+func (s *Server) sign(v url.Values) string {
+	mac := hmac.New(sha256.New, s.signKey)
+	for _, f := range strings.Split(v.Get("openid.signed"), ",") {
+		fmt.Fprintf(mac, "%s:%s\n", f, v.Get("openid."+f))
+	}
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Server) handleInventory(w http.ResponseWriter, r *http.Request) {
+	inv, pageSize, ok := s.inventory(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	if inv == nil || inv.Error != "" || pageSize <= 0 {
+		writeJSON(w, inv)
+		return
+	}
+
+	start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+	writeJSON(w, page(inv, start, pageSize))
+}
+
+// handleInventoryPage serves the asset id cursor paginated inventory
+// endpoint, private profiles responds forbidden with null body.
+func (s *Server) handleInventoryPage(w http.ResponseWriter, r *http.Request) {
+	inv, pageSize, ok := s.inventory(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	if inv == nil {
+		writeJSON(w, nil)
+		return
+	}
+	if inv.Error != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("null"))
+		return
+	}
+
+	count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+	if pageSize > 0 && (count <= 0 || count > pageSize) {
+		count = pageSize
+	}
+	writeJSON(w, cursorPage(inv, r.URL.Query().Get("start_assetid"), count))
+}
+
+// inventory returns the inventory of steam id or writes the configured
+// error response, nil inventory means an unknown profile.
+func (s *Server) inventory(w http.ResponseWriter, steamID string) (*steam.RawInventory, int, bool) {
+	s.mu.Lock()
+	limited := s.limited[steamID] > 0
+	if limited {
+		s.limited[steamID]--
+	}
+	status, hasStatus := s.statuses[steamID]
+	inv := s.inventories[steamID]
+	pageSize := s.PageSize
+	s.mu.Unlock()
+
+	switch {
+	case limited:
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		return nil, 0, false
+	case hasStatus:
+		w.WriteHeader(status)
+		return nil, 0, false
+	}
+	return inv, pageSize, true
+}
+
+// page slices inventory assets sorted by asset id with its descriptions.
+func page(inv *steam.RawInventory, start, size int) *steam.RawInventory {
+	ids := sortedAssetIDs(inv)
+	start = min(max(start, 0), len(ids))
+	end := min(start+size, len(ids))
+	p := &steam.RawInventory{
+		Success: true,
+		RgInvs:  map[string]steam.RawInventoryAsset{},
+		RgDescs: map[string]steam.RawInventoryDesc{},
+	}
+	for _, id := range ids[start:end] {
+		a := inv.RgInvs[id]
+		p.RgInvs[id] = a
+		key := a.ClassID + "_" + a.InstanceID
+		if d, ok := inv.RgDescs[key]; ok {
+			p.RgDescs[key] = d
+		}
+	}
+	if end < len(ids) {
+		p.More = true
+		p.MoreStart = steam.RawInventoryPageOffset(end)
+	}
+	return p
+}
+
+// cursorPage slices inventory assets sorted by asset id after the start
+// asset id, zero count serves the rest of the inventory.
+func cursorPage(inv *steam.RawInventory, startAssetID string, count int) *steam.InventoryPage {
+	ids := sortedAssetIDs(inv)
+	start := 0
+	if startAssetID != "" {
+		start, _ = slices.BinarySearch(ids, startAssetID)
+		if start < len(ids) && ids[start] == startAssetID {
+			start++
+		}
+	}
+	end := len(ids)
+	if count > 0 {
+		end = min(start+count, len(ids))
+	}
+
+	p := &steam.InventoryPage{
+		Assets:              []steam.RawInventoryAsset{},
+		Descriptions:        []steam.RawInventoryDesc{},
+		TotalInventoryCount: len(ids),
+		Success:             1,
+	}
+	seen := map[string]bool{}
+	for _, id := range ids[start:end] {
+		a := inv.RgInvs[id]
+		a.AssetID, a.ID = id, ""
+		p.Assets = append(p.Assets, a)
+		key := a.ClassID + "_" + a.InstanceID
+		if d, ok := inv.RgDescs[key]; ok && !seen[key] {
+			seen[key] = true
+			p.Descriptions = append(p.Descriptions, d)
+		}
+	}
+	if end < len(ids) {
+		p.MoreItems = 1
+		p.LastAssetID = ids[end-1]
+	}
+	return p
+}
+
+func sortedAssetIDs(inv *steam.RawInventory) []string {
+	ids := make([]string, 0, len(inv.RgInvs))
+	for id := range inv.RgInvs {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func (s *Server) handlePlayerSummaries(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(w, r) {
+		return
+	}
+
+	type response struct {
+		Response struct {
+			Players []steam.PlayerSummaries `json:"players"`
+		} `json:"response"`
+	}
+	var res response
+	s.mu.Lock()
+	for _, id := range strings.Split(r.URL.Query().Get("steamids"), ",") {
+		if p, ok := s.players[id]; ok {
+			res.Response.Players = append(res.Response.Players, p)
+		}
+	}
+	s.mu.Unlock()
+	writeJSON(w, res)
+}
+
+func (s *Server) handleResolveVanityURL(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(w, r) {
+		return
+	}
+
+	type response struct {
+		Response struct {
+			SteamID string `json:"steamid,omitempty"`
+			Success int    `json:"success"`
+			Message string `json:"message,omitempty"`
+		} `json:"response"`
+	}
+	var res response
+	s.mu.Lock()
+	steamID, ok := s.vanities[r.URL.Query().Get("vanityurl")]
+	s.mu.Unlock()
+	if ok {
+		res.Response.SteamID = steamID
+		res.Response.Success = 1
+	} else {
+		res.Response.Success = 42
+		res.Response.Message = "No match"
+	}
+	writeJSON(w, res)
+}
+
+// handleLogin redirects back to the return url as signed in steam id like
+// the user approved the login.
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	returnTo := r.URL.Query().Get("openid.return_to")
+	u, err := url.Parse(returnTo)
+	if err != nil || returnTo == "" {
+		http.Error(w, "invalid openid.return_to", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	steamID := s.loginID
+	s.mu.Unlock()
+
+	v := url.Values{}
+	if steamID == "" {
+		v.Set("openid.ns", openidNs)
+		v.Set("openid.mode", "cancel")
+	} else {
+		v = s.Assertion(returnTo, steamID)
+	}
+	q := u.Query()
+	for k := range v {
+		q.Set(k, v.Get(k))
+	}
+	u.RawQuery = q.Encode()
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+// handleCheckAuthentication verifies assertion signature using key-value
+// form encoding response.
+func (s *Server) handleCheckAuthentication(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.PostForm.Get("openid.mode") != "check_authentication" {
+		http.Error(w, "invalid openid.mode", http.StatusBadRequest)
+		return
+	}
+
+	valid := hmac.Equal([]byte(r.PostForm.Get("openid.sig")), []byte(s.sign(r.PostForm)))
+	fmt.Fprintf(w, "ns:%s\nis_valid:%t\n", openidNs, valid)
+}
+
+func (s *Server) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if r.URL.Query().Get("key") != Key {
+		http.Error(w, "<html><body><h1>Forbidden</h1></body></html>", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
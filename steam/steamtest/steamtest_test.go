@@ -0,0 +1,225 @@
+package steamtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kudarap/dotagiftx/steam"
+)
+
+const testSteamID = "76561198088587178"
+
+type testCache map[string]string
+
+func (c testCache) Set(key string, val interface{}, _ time.Duration) error {
+	c[key] = val.(string)
+	return nil
+}
+
+func (c testCache) Get(key string) (string, error) { return c[key], nil }
+
+func newTestClient(t *testing.T) (*Server, *steam.Client) {
+	t.Helper()
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+
+	cfg := srv.Config()
+	cfg.Realm = "http://localhost:3000"
+	cfg.Return = "http://localhost:3000/login"
+	client, err := steam.New(cfg, testCache{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv, client
+}
+
+func TestServer_login(t *testing.T) {
+	srv, client := newTestClient(t)
+	srv.SetPlayer(steam.PlayerSummaries{SteamId: testSteamID, PersonaName: "kudarap"})
+	srv.LoginAs(testSteamID)
+
+	authURL, err := client.AuthorizeURL(httptest.NewRequest(http.MethodGet, "http://localhost:3000/login", nil))
+	if err != nil {
+		t.Fatalf("AuthorizeURL() error = %v", err)
+	}
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	res, err := noRedirect.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	callback := httptest.NewRequest(http.MethodGet, res.Header.Get("Location"), nil)
+	player, err := client.Authenticate(callback)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if player.ID != testSteamID || player.Name != "kudarap" {
+		t.Errorf("Authenticate() = %+v, want %s kudarap", player, testSteamID)
+	}
+
+	// Tampered assertion should not be valid.
+	v := srv.Assertion("http://localhost:3000/login", testSteamID)
+	v.Set("openid.claimed_id", srv.URL+"/openid/id/76561198000000000")
+	tampered := httptest.NewRequest(http.MethodGet, "http://localhost:3000/login?"+v.Encode(), nil)
+	if _, err = client.Authenticate(tampered); err == nil {
+		t.Errorf("Authenticate() tampered assertion should fail")
+	}
+}
+
+func TestServer_inventory(t *testing.T) {
+	srv, client := newTestClient(t)
+	if err := srv.LoadInventory(testSteamID, "../testdata/sample.json"); err != nil {
+		t.Fatal(err)
+	}
+	srv.SetPrivate("76561198000000001")
+	srv.SetInventoryStatus("76561198000000002", http.StatusForbidden)
+	srv.SetInventoryStatus("76561198000000003", http.StatusNotFound)
+	srv.SetRateLimited("76561198000000004", 10)
+
+	ctx := context.Background()
+	assets, err := client.InventoryAsset(ctx, testSteamID)
+	if err != nil || len(assets) == 0 {
+		t.Errorf("InventoryAsset() = %d assets, error %v, want assets", len(assets), err)
+	}
+
+	tests := []struct {
+		steamID string
+		want    error
+	}{
+		{"76561198000000001", steam.ErrInventoryPrivate},
+		{"76561198000000002", steam.ErrInventoryPrivate},
+		{"76561198000000003", steam.ErrNotFound},
+		{"76561198000000004", steam.ErrRateLimited},
+	}
+	for _, tt := range tests {
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		_, err = client.InventoryAsset(ctx, tt.steamID)
+		cancel()
+		if !errors.Is(err, tt.want) {
+			t.Errorf("InventoryAsset(%s) error = %v, want %v", tt.steamID, err, tt.want)
+		}
+	}
+
+	// Retries after rate limited responses.
+	srv.SetRateLimited(testSteamID, 2)
+	if _, err = client.InventoryAsset(ctx, testSteamID); err != nil {
+		t.Errorf("InventoryAsset() rate limited twice error = %v, want retried", err)
+	}
+}
+
+func TestServer_inventoryPages(t *testing.T) {
+	srv, _ := newTestClient(t)
+	srv.SetInventory(testSteamID, &steam.RawInventory{
+		Success: true,
+		RgInvs: map[string]steam.RawInventoryAsset{
+			"1": {ID: "1", ClassID: "c1", InstanceID: "0"},
+			"2": {ID: "2", ClassID: "c2", InstanceID: "0"},
+			"3": {ID: "3", ClassID: "c3", InstanceID: "0"},
+		},
+		RgDescs: map[string]steam.RawInventoryDesc{
+			"c1_0": {ClassID: "c1", InstanceID: "0", Name: "one"},
+			"c2_0": {ClassID: "c2", InstanceID: "0", Name: "two"},
+			"c3_0": {ClassID: "c3", InstanceID: "0", Name: "three"},
+		},
+	})
+	srv.PageSize = 2
+
+	var pages []*steam.RawInventory
+	start := 0
+	for range 3 {
+		res, err := http.Get(fmt.Sprintf("%s/profiles/%s/inventory/json/570/2?start=%d", srv.URL, testSteamID, start))
+		if err != nil {
+			t.Fatal(err)
+		}
+		inv := &steam.RawInventory{}
+		err = json.NewDecoder(res.Body).Decode(inv)
+		res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, inv)
+		if !inv.More {
+			break
+		}
+		start = int(inv.MoreStart)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("inventory pages = %d, want 2", len(pages))
+	}
+	if len(pages[0].RgInvs) != 2 || len(pages[0].RgDescs) != 2 || pages[0].MoreStart != 2 {
+		t.Errorf("inventory first page = %+v, want 2 assets more start 2", pages[0])
+	}
+	if len(pages[1].RgInvs) != 1 || pages[1].More {
+		t.Errorf("inventory last page = %+v, want 1 asset without more", pages[1])
+	}
+}
+
+func TestServer_allInventory(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	if err := srv.LoadInventory(testSteamID, "../testdata/sample.json"); err != nil {
+		t.Fatal(err)
+	}
+	whole, err := steam.New(srv.Config(), testCache{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := whole.AllInventory(context.Background(), testSteamID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.PageSize = 100
+
+	tests := []struct {
+		name     string
+		maxPages int
+		maxBytes int64
+		wantErr  error
+	}{
+		{"all pages", 0, 0, nil},
+		{"over max pages", 1, 0, steam.ErrInventoryTooLarge},
+		{"over max bytes", 0, 512, steam.ErrInventoryTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := srv.Config()
+			cfg.InventoryMaxPages = tt.maxPages
+			cfg.InventoryMaxBytes = tt.maxBytes
+			client, _ := steam.New(cfg, testCache{}, nil)
+
+			got, err := client.AllInventory(context.Background(), testSteamID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AllInventory() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got.AllInvs) != len(want.AllInvs) || len(got.AllDescs) != len(want.AllDescs) {
+				t.Errorf("AllInventory() = %d assets %d descs, want %d assets %d descs",
+					len(got.AllInvs), len(got.AllDescs), len(want.AllInvs), len(want.AllDescs))
+			}
+		})
+	}
+}
+
+func TestServer_resolveVanityURL(t *testing.T) {
+	srv, client := newTestClient(t)
+	srv.SetVanity("kudarap", testSteamID)
+
+	got, err := client.ResolveVanityURL(steam.VanityPrefixID + "kudarap")
+	if err != nil || got != testSteamID {
+		t.Errorf("ResolveVanityURL() = %s, %v, want %s", got, err, testSteamID)
+	}
+	if _, err = client.ResolveVanityURL(steam.VanityPrefixID + "unknown"); err == nil {
+		t.Errorf("ResolveVanityURL() unknown vanity should fail")
+	}
+}
@@ -1,12 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/kudarap/dotagiftx/steaminvorg"
 )
 
 func main() {
-	inv, err := steaminvorg.SWR("76561198088587178", true)
+	inv, err := steaminvorg.SWR(context.Background(), "76561198088587178", true)
 	fmt.Println(inv.ToAssets(), err)
 }
@@ -2,29 +2,135 @@ package steaminvorg
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/logging"
 	"github.com/kudarap/dotagiftx/steam"
 )
 
 const (
-	providerID = "steaminvorg"
+	// ProviderID is the inventory provider name of steaminventory.org.
+	ProviderID = "steaminvorg"
+
+	defaultMetaURL    = "https://db.steaminventory.org"
+	defaultDataURL    = "https://data-gz.steaminventory.org"
+	defaultCrawlURL   = "https://job.steaminventory.org"
+	defaultRetries    = 10
+	defaultRetryDelay = time.Second * 5
+	defaultFreshCache = time.Minute * 15
+	defaultTimeout    = time.Second * 30
+
+	// maxResponseBytes limits aggregated inventory response body.
+	maxResponseBytes = 64 << 20
+)
 
-	maxGetRetries = 10
-	retrySleepDur = time.Second * 5
-	// freshCacheDur = time.Hour
-	freshCacheDur = time.Minute * 15
+// Steaminvorg request errors, not found and private wraps the steam errors
+// so callers can handle them the same way.
+var (
+	ErrCrawlPending = errors.New("steaminvorg crawl pending")
+	ErrNotFound     = fmt.Errorf("steaminvorg: %w", steam.ErrNotFound)
+	ErrPrivate      = fmt.Errorf("steaminvorg: %w", steam.ErrInventoryPrivate)
 )
 
-// InventoryAsset returns a compact format from all inventory data.
+// StatusError represents unexpected response status code.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("steaminvorg response status %d", e.Code)
+}
+
+// Config represents steaminvorg config.
+type Config struct {
+	// MetaURL, DataURL and CrawlURL overrides steaminventory.org base urls
+	// of queue state, aggregated inventory and crawl schedule.
+	MetaURL  string `envconfig:"META_URL"`
+	DataURL  string `envconfig:"DATA_URL"`
+	CrawlURL string `envconfig:"CRAWL_URL"`
+	// Retries of queue state checks after a crawl request and RetryDelay is
+	// the base wait between them.
+	Retries    int
+	RetryDelay time.Duration `envconfig:"RETRY_DELAY"`
+	// FreshCache is how long crawled inventory is used without re-crawling.
+	FreshCache time.Duration `envconfig:"FRESH_CACHE"`
+}
+
+func (c Config) setDefault() Config {
+	c.MetaURL = strings.TrimRight(c.MetaURL, "/")
+	if c.MetaURL == "" {
+		c.MetaURL = defaultMetaURL
+	}
+	c.DataURL = strings.TrimRight(c.DataURL, "/")
+	if c.DataURL == "" {
+		c.DataURL = defaultDataURL
+	}
+	c.CrawlURL = strings.TrimRight(c.CrawlURL, "/")
+	if c.CrawlURL == "" {
+		c.CrawlURL = defaultCrawlURL
+	}
+	if c.Retries <= 0 {
+		c.Retries = defaultRetries
+	}
+	if c.RetryDelay <= 0 {
+		c.RetryDelay = defaultRetryDelay
+	}
+	if c.FreshCache <= 0 {
+		c.FreshCache = defaultFreshCache
+	}
+	return c
+}
+
+// Client represents a steaminvorg client.
+type Client struct {
+	config Config
+	http   *http.Client
+	logger logging.Logger
+}
+
+// New create new steaminvorg client instance, http client with default
+// timeout will be used when hc is nil.
+func New(c Config, hc *http.Client, lg logging.Logger) *Client {
+	if hc == nil {
+		hc = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Client{c.setDefault(), hc, lg}
+}
+
+func init() {
+	dotagiftx.RegisterInventoryProviderFactory(ProviderID, func(s dotagiftx.InventoryProviderSetup) (dotagiftx.InventoryProvider, error) {
+		c, _ := dotagiftx.SetupValue[Config](s)
+		return New(c, nil, logging.DefaultWithPrefix(ProviderID)), nil
+	})
+}
+
+// defaultClient serves the package level requests.
+var defaultClient = New(Config{}, nil, logging.DefaultWithPrefix(ProviderID))
+
+// InventoryAsset returns a compact format from all inventory data using the default client.
 func InventoryAsset(ctx context.Context, steamID string) ([]steam.Asset, error) {
-	inv, err := SWR(steamID, false)
+	return defaultClient.InventoryAsset(ctx, steamID)
+}
+
+func InventoryAssetWithProvider(ctx context.Context, steamID string) (string, []steam.Asset, error) {
+	return defaultClient.InventoryAssetWithProvider(ctx, steamID)
+}
+
+// SWR stale-while-re-invalidating crawled data using the default client.
+func SWR(ctx context.Context, steamID string, strict bool) (*steam.AllInventory, error) {
+	return defaultClient.SWR(ctx, steamID, strict)
+}
+
+// InventoryAsset returns a compact format from all inventory data.
+func (c *Client) InventoryAsset(ctx context.Context, steamID string) ([]steam.Asset, error) {
+	inv, err := c.SWR(ctx, steamID, false)
 	if err != nil {
 		return nil, err
 	}
@@ -32,64 +138,90 @@ func InventoryAsset(ctx context.Context, steamID string) ([]steam.Asset, error)
 	return inv.ToAssets(), nil
 }
 
-func InventoryAssetWithProvider(ctx context.Context, steamID string) (string, []steam.Asset, error) {
-	res, err := InventoryAsset(ctx, steamID)
-	return providerID, res, err
+func (c *Client) InventoryAssetWithProvider(ctx context.Context, steamID string) (string, []steam.Asset, error) {
+	res, err := c.InventoryAsset(ctx, steamID)
+	return ProviderID, res, err
 }
 
-// SWR stale-while-re-invalidating crawled data.
-func SWR(steamID string, strict bool) (*steam.AllInventory, error) {
+func (c *Client) Name() string { return ProviderID }
+
+// Capabilities returns stale-while-revalidate inventory that is fresh up to
+// the cache duration.
+func (c *Client) Capabilities() dotagiftx.ProviderCapabilities {
+	return dotagiftx.ProviderCapabilities{MaxAge: c.config.FreshCache, Cached: true}
+}
+
+// Invalidate does nothing since the cache is kept on steaminvorg side.
+func (c *Client) Invalidate(context.Context, string) error { return nil }
+
+func (c *Client) Health(context.Context) error { return nil }
+
+// SWR stale-while-re-invalidating crawled data, strict fails when the queue
+// state could not be checked before crawling.
+func (c *Client) SWR(ctx context.Context, steamID string, strict bool) (*steam.AllInventory, error) {
 	// check for freshly cached inventory
-	m, err := GetMeta(steamID)
-	if err != nil && strict {
-		log.Println("STEAMINVORG META ERR", steamID, err)
+	m, err := c.GetMeta(ctx, steamID)
+	if err != nil && (strict || ctx.Err() != nil) {
+		c.logger.Errorf("meta check failed steam_id=%s err=%q", steamID, err)
 		return nil, err
 	}
-	if m != nil && m.isCacheFresh() {
-		log.Println("STEAMINVORG CACHED", steamID)
-		return Get(steamID)
+	if m != nil && c.isCacheFresh(m) {
+		c.logger.Debugf("cache fresh steam_id=%s last_updated=%s", steamID, m.LastUpdated)
+		return c.Get(ctx, steamID)
 	}
 
-	log.Println("STEAMINVORG CRAWL REQUEST", steamID)
-	if _, err = Crawl(steamID); err != nil {
-		log.Println("STEAMINVORG CRAWL REQUEST ERR", steamID, err)
+	c.logger.Debugf("crawl request steam_id=%s", steamID)
+	if _, err = c.Crawl(ctx, steamID); err != nil {
+		c.logger.Errorf("crawl request failed steam_id=%s err=%q", steamID, err)
 		return nil, err
 	}
 
 	// check for meta until processed with a little bit of back-off
-	for i := 1; i <= maxGetRetries; i++ {
-		log.Println("STEAMINVORG TRY", steamID, i)
-		m, err = GetMeta(steamID)
-		if err != nil {
-			log.Println("STEAMINVORG TRY ERR", steamID, err)
-			return nil, err
-		}
-		if m != nil && m.Status == "success" {
-			log.Println("STEAMINVORG TRY SUCCESS", steamID, i)
-			break
-		}
-
-		if i == maxGetRetries {
-			log.Println("STEAMINVORG TIMED OUT", steamID)
-		}
-		time.Sleep(retrySleepDur + time.Duration(i)*time.Second)
+	if err = c.waitCrawl(ctx, steamID); err != nil {
+		c.logger.Errorf("crawl failed steam_id=%s err=%q", steamID, err)
+		return nil, err
 	}
 
-	res, err := Get(steamID)
+	res, err := c.Get(ctx, steamID)
 	if err != nil {
-		log.Println("STEAMINVORG GET ERR", steamID, err)
+		c.logger.Errorf("get failed steam_id=%s err=%q", steamID, err)
 		return nil, err
 	}
 
-	log.Println("STEAMINVORG GET DONE", steamID)
+	c.logger.Debugf("get done steam_id=%s", steamID)
 	return res, nil
 }
 
+// waitCrawl checks queue state until the crawl succeeds, returns
+// ErrCrawlPending when retries runs out.
+func (c *Client) waitCrawl(ctx context.Context, steamID string) error {
+	for i := 1; i <= c.config.Retries; i++ {
+		delay := c.config.RetryDelay + time.Duration(i)*c.config.RetryDelay/5
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+
+		m, err := c.GetMeta(ctx, steamID)
+		if err != nil {
+			return err
+		}
+		if m != nil && m.Status == "success" {
+			c.logger.Debugf("crawl success steam_id=%s try=%d", steamID, i)
+			return nil
+		}
+		c.logger.Debugf("crawl waiting steam_id=%s try=%d", steamID, i)
+	}
+	return ErrCrawlPending
+}
+
 // GetMeta https://db.steaminventory.org/SteamInventory/76561198264023028 - check queue state
-func GetMeta(steamID string) (*Metadata, error) {
-	url := fmt.Sprintf("https://db.steaminventory.org/SteamInventory/%s", steamID)
+func (c *Client) GetMeta(ctx context.Context, steamID string) (*Metadata, error) {
 	var raw rawMetadata
-	if err := getRequest(url, &raw); err != nil {
+	if err := c.getRequest(ctx, c.config.MetaURL+"/SteamInventory/"+steamID, &raw); err != nil {
 		return nil, err
 	}
 
@@ -105,10 +237,9 @@ func GetMeta(steamID string) (*Metadata, error) {
 
 // Get https://data.steaminventory.org/SteamInventory/76561198264023028 - aggregated inventory
 // https://data-gz.steaminventory.org/SteamInventory/76561198264023028 - aggregated inventory gzipped
-func Get(steamID string) (*steam.AllInventory, error) {
-	url := fmt.Sprintf("https://data-gz.steaminventory.org/SteamInventory/%s", steamID)
+func (c *Client) Get(ctx context.Context, steamID string) (*steam.AllInventory, error) {
 	all := &steam.AllInventory{}
-	if err := getRequest(url, all); err != nil {
+	if err := c.getRequest(ctx, c.config.DataURL+"/SteamInventory/"+steamID, all); err != nil {
 		return nil, err
 	}
 
@@ -116,26 +247,19 @@ func Get(steamID string) (*steam.AllInventory, error) {
 }
 
 // Crawl POST https://job.steaminventory.org/ScheduleInventoryCrawl?profile=76561198088587178
-func Crawl(steamID string) (status string, err error) {
-	url := fmt.Sprintf("https://job.steaminventory.org/ScheduleInventoryCrawl?profile=%s", steamID)
-	res, err := http.Post(url, "", nil)
-	if err != nil {
-		return
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
+func (c *Client) Crawl(ctx context.Context, steamID string) (status string, err error) {
+	u := c.config.CrawlURL + "/ScheduleInventoryCrawl?profile=" + steamID
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, nil)
 	if err != nil {
-		return
+		return "", err
 	}
 
 	crawlRes := struct {
 		Status string `json:"status"`
 	}{}
-	if err = json.Unmarshal(body, &crawlRes); err != nil {
-		return
+	if err = c.do(req, &crawlRes); err != nil {
+		return "", err
 	}
-
 	return crawlRes.Status, nil
 }
 
@@ -154,19 +278,19 @@ type Metadata struct {
 func (m Metadata) hasError() error {
 	switch m.Status {
 	case "error:403":
-		return steam.ErrInventoryPrivate
+		return ErrPrivate
 	case "error:404":
-		return fmt.Errorf("inventory not found")
+		return ErrNotFound
 	}
 
 	return nil
 }
 
-func (m Metadata) isCacheFresh() bool {
+func (c *Client) isCacheFresh(m *Metadata) bool {
 	if m.LastUpdated.IsZero() {
 		return false
 	}
-	return time.Now().Before(m.LastUpdated.Add(freshCacheDur))
+	return time.Now().Before(m.LastUpdated.Add(c.config.FreshCache))
 }
 
 type rawMetadata struct {
@@ -224,23 +348,34 @@ func (d *rawMetadata) format() *Metadata {
 	return m
 }
 
-func getRequest(url string, data interface{}) error {
-	res, err := http.Get(url)
+func (c *Client) getRequest(ctx context.Context, url string, data interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, data)
+}
+
+// do sends the request and decodes response body into data.
+func (c *Client) do(req *http.Request, data interface{}) error {
+	res, err := c.http.Do(req)
 	if err != nil {
 		return err
 	}
 	defer res.Body.Close()
 
-	if res.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("not found")
+	// Missing endpoint or data is not an inventory answer, only the crawl
+	// status error:404 tells the inventory does not exist.
+	if res.StatusCode >= 300 {
+		return &StatusError{res.StatusCode}
 	}
 
-	body, err := io.ReadAll(res.Body)
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseBytes))
 	if err != nil {
 		return err
 	}
-	if err = json.Unmarshal(body, data); err != nil {
-		return err
+	if err = fastjson.Unmarshal(body, data); err != nil {
+		return fmt.Errorf("decode %s: %s", req.URL.Path, err)
 	}
 
 	return nil
@@ -1,9 +1,18 @@
 package steaminvorg
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/kudarap/dotagiftx/logging"
+	"github.com/kudarap/dotagiftx/steam"
 )
 
 func TestMetadata_isCacheFresh(t *testing.T) {
@@ -15,9 +24,10 @@ func TestMetadata_isCacheFresh(t *testing.T) {
 		{time.Date(2021, time.November, 10, 23, 0, 0, 0, time.UTC), false},
 		{time.Date(2029, time.November, 10, 23, 0, 0, 0, time.UTC), true},
 	}
+	c := New(Config{}, nil, logging.Default())
 	for _, tt := range tests {
 		m := Metadata{LastUpdated: tt.lastUpdated}
-		if got := m.isCacheFresh(); got != tt.isFresh {
+		if got := c.isCacheFresh(&m); got != tt.isFresh {
 			t.Errorf("isCacheFresh() = %v, want %v", got, tt.isFresh)
 		}
 	}
@@ -76,3 +86,132 @@ func Test_parseStrTime(t *testing.T) {
 		}
 	}
 }
+
+// fakeSteaminvorg stands in for steaminventory.org services, statuses are
+// the queue states returned on each meta check.
+type fakeSteaminvorg struct {
+	lastUpdated time.Time
+	statuses    []string
+
+	mu      sync.Mutex
+	crawled int
+	checks  int
+}
+
+func (f *fakeSteaminvorg) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/db/SteamInventory/1":
+		if len(f.statuses) == 0 {
+			fmt.Fprint(w, `{"Count":0,"Items":[]}`)
+			return
+		}
+		status := f.statuses[min(f.checks, len(f.statuses)-1)]
+		f.checks++
+		fmt.Fprintf(w, `{"Count":1,"Items":[{"status":{"S":%q},"index_timestamp":{"N":"%d"}}]}`,
+			status, f.lastUpdated.UnixMilli())
+	case "/data/SteamInventory/1":
+		fmt.Fprint(w, `{
+			"allInventory":[{"assetid":"100","classid":"1","instanceid":"0"}],
+			"allDescriptions":{"1_0":{"classid":"1","instanceid":"0","name":"Dirge Amplifier"}}
+		}`)
+	case "/job/ScheduleInventoryCrawl":
+		f.crawled++
+		fmt.Fprint(w, `{"status":"queued"}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestClient(t *testing.T, f *fakeSteaminvorg) *Client {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return New(Config{
+		MetaURL:    srv.URL + "/db",
+		DataURL:    srv.URL + "/data",
+		CrawlURL:   srv.URL + "/job",
+		Retries:    3,
+		RetryDelay: time.Millisecond,
+	}, srv.Client(), logging.Default())
+}
+
+func TestClient_InventoryAsset(t *testing.T) {
+	tests := []struct {
+		name        string
+		fake        *fakeSteaminvorg
+		wantCrawled int
+		wantErr     error
+	}{
+		{"fresh cache", &fakeSteaminvorg{
+			lastUpdated: time.Now(),
+			statuses:    []string{"success"},
+		}, 0, nil},
+		{"stale cache crawled", &fakeSteaminvorg{
+			lastUpdated: time.Now().Add(-time.Hour),
+			statuses:    []string{"success", "queued", "success"},
+		}, 1, nil},
+		{"never crawled", &fakeSteaminvorg{}, 1, ErrCrawlPending},
+		{"crawl pending", &fakeSteaminvorg{
+			statuses: []string{"queued"},
+		}, 1, ErrCrawlPending},
+		{"private", &fakeSteaminvorg{
+			statuses: []string{"queued", "error:403"},
+		}, 1, steam.ErrInventoryPrivate},
+		{"not found", &fakeSteaminvorg{
+			statuses: []string{"queued", "error:404"},
+		}, 1, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, tt.fake)
+			got, err := c.InventoryAsset(context.Background(), "1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("InventoryAsset() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.fake.crawled != tt.wantCrawled {
+				t.Errorf("InventoryAsset() crawled %d times, want %d", tt.fake.crawled, tt.wantCrawled)
+			}
+			if err == nil && (len(got) != 1 || got[0].AssetID != "100") {
+				t.Errorf("InventoryAsset() = %+v, want asset 100", got)
+			}
+		})
+	}
+}
+
+func TestClient_SWR_contextCanceled(t *testing.T) {
+	c := newTestClient(t, &fakeSteaminvorg{statuses: []string{"queued"}})
+	c.config.RetryDelay = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	start := time.Now()
+	if _, err := c.SWR(ctx, "1", false); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SWR() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("SWR() took %s after context is done", time.Since(start))
+	}
+}
+
+func TestClient_statusError(t *testing.T) {
+	for _, code := range []int{http.StatusNotFound, http.StatusBadGateway} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(code)
+		}))
+		c := New(Config{MetaURL: srv.URL}, srv.Client(), logging.Default())
+
+		var se *StatusError
+		_, err := c.SWR(context.Background(), "1", true)
+		if !errors.As(err, &se) || se.Code != code {
+			t.Errorf("SWR() error = %v, want status error %d", err, code)
+		}
+		// Response status is a provider failure and not an inventory answer.
+		if errors.Is(err, steam.ErrNotFound) {
+			t.Errorf("SWR() status %d error = %v, should not be inventory not found", code, err)
+		}
+		srv.Close()
+	}
+}
@@ -15,6 +15,10 @@ const (
 	TaskPriorityLow    TaskPriority = 3
 )
 
+// TaskPayloadVersion current version of task payloads. Bump this when payload
+// fields changes that are not backward compatible.
+const TaskPayloadVersion = 1
+
 // Task status.
 const (
 	TaskStatusPending    TaskStatus = 0
@@ -46,8 +50,33 @@ type (
 		CreatedAt *time.Time   `json:"created_at"   db:"created_at,omitempty,index"`
 		UpdatedAt *time.Time   `json:"updated_at"   db:"updated_at,omitempty"`
 	}
+
+	// VerifyInventoryPayload represents TaskTypeVerifyInventory payload.
+	//
+	// Market, user, and item details are reloaded by ID when processing
+	// the task instead of relying on a stale snapshot.
+	VerifyInventoryPayload struct {
+		Version  int    `json:"version"   db:"version"`
+		MarketID string `json:"market_id" db:"market_id"`
+	}
+
+	// VerifyDeliveryPayload represents TaskTypeVerifyDelivery payload.
+	VerifyDeliveryPayload struct {
+		Version  int    `json:"version"   db:"version"`
+		MarketID string `json:"market_id" db:"market_id"`
+	}
 )
 
+// NewVerifyInventoryPayload returns current version of inventory verification payload.
+func NewVerifyInventoryPayload(marketID string) VerifyInventoryPayload {
+	return VerifyInventoryPayload{TaskPayloadVersion, marketID}
+}
+
+// NewVerifyDeliveryPayload returns current version of delivery verification payload.
+func NewVerifyDeliveryPayload(marketID string) VerifyDeliveryPayload {
+	return VerifyDeliveryPayload{TaskPayloadVersion, marketID}
+}
+
 var taskTypeStrings = map[TaskType]string{
 	TaskTypeVerifyDelivery:  "verify_delivery",
 	TaskTypeVerifyInventory: "verify_inventory",
@@ -0,0 +1,68 @@
+// Verifyreplay reruns inventory and delivery verification over captured raw
+// inventories and reports status changes from the baseline, serves as
+// regression check of verification rules before deploys.
+//
+// Usage:
+//
+//	verifyreplay -dir inventories -cases cases.csv -out results.csv
+//
+// Inventory directory contains raw inventories named by steam id on steam,
+// phantasm or steaminvorg format. Results are written on the same format as
+// cases that can be used as baseline for the next rule version.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+var bar = strings.Repeat("-", 70)
+
+func main() {
+	dirPtr := flag.String("dir", "inventories", "directory of captured raw inventories")
+	casesPtr := flag.String("cases", "cases.csv", "market cases csv with baseline status")
+	outPtr := flag.String("out", "", "write replay results csv for the next baseline")
+	flag.Parse()
+
+	f, err := os.Open(*casesPtr)
+	if err != nil {
+		log.Fatalln("could not open cases:", err)
+	}
+	cases, err := readCases(f)
+	f.Close()
+	if err != nil {
+		log.Fatalln("could not read cases:", err)
+	}
+
+	results, changes := replay(context.Background(), fileSource(*dirPtr), cases)
+	if *outPtr != "" {
+		out, err := os.Create(*outPtr)
+		if err != nil {
+			log.Fatalln("could not create results:", err)
+		}
+		if err = writeCases(out, results); err != nil {
+			log.Fatalln("could not write results:", err)
+		}
+		if err = out.Close(); err != nil {
+			log.Fatalln("could not close results:", err)
+		}
+	}
+
+	for _, c := range changes {
+		fmt.Println(bar)
+		fmt.Printf("%s %s %s (%s)\n", c.Kind, c.MarketID, c.SteamID, c.Item)
+		fmt.Printf("status: %s -> %s confidence: %s -> %d\n", c.Case.Status, c.Status, c.Case.Confidence, c.Confidence)
+		for _, e := range c.Evidence {
+			fmt.Println("evidence:", e)
+		}
+	}
+	fmt.Println(bar)
+	fmt.Printf("replayed %d cases, %d status changes\n", len(results), len(changes))
+	if len(changes) != 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/phantasm"
+	"github.com/kudarap/dotagiftx/steam"
+	"github.com/kudarap/dotagiftx/verify"
+)
+
+var fastjson = jsoniter.ConfigFastest
+
+const (
+	caseInventory = "inventory"
+	caseDelivery  = "delivery"
+)
+
+// caseHeader columns of market cases and replay results csv, personas are
+// separated by "|" and reserved at is in RFC3339 format.
+var caseHeader = []string{"kind", "market_id", "steam_id", "item", "personas", "reserved_at", "status", "confidence"}
+
+// Case represents a market verification case to replay.
+type Case struct {
+	Kind       string
+	MarketID   string
+	SteamID    string
+	Item       string
+	Personas   []string
+	ReservedAt *time.Time
+	// Status is the baseline status text from the previous rule version.
+	Status     string
+	Confidence string
+}
+
+// Change represents a case status change between rule versions.
+type Change struct {
+	Case
+	Status     string
+	Confidence int
+	Evidence   []string
+}
+
+func readCases(r io.Reader) ([]Case, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []Case
+	for i, row := range rows {
+		if i == 0 && len(row) != 0 && row[0] == caseHeader[0] {
+			continue
+		}
+		if len(row) < 4 {
+			return nil, fmt.Errorf("line %d: kind, market_id, steam_id and item are required", i+1)
+		}
+		row = append(row, make([]string, len(caseHeader)-min(len(row), len(caseHeader)))...)
+
+		c := Case{
+			Kind:       strings.ToLower(strings.TrimSpace(row[0])),
+			MarketID:   strings.TrimSpace(row[1]),
+			SteamID:    strings.TrimSpace(row[2]),
+			Item:       strings.TrimSpace(row[3]),
+			Status:     strings.TrimSpace(row[6]),
+			Confidence: strings.TrimSpace(row[7]),
+		}
+		if c.Kind != caseInventory && c.Kind != caseDelivery {
+			return nil, fmt.Errorf("line %d: unknown kind %q", i+1, c.Kind)
+		}
+		for _, p := range strings.Split(row[4], "|") {
+			if p = strings.TrimSpace(p); p != "" {
+				c.Personas = append(c.Personas, p)
+			}
+		}
+		if s := strings.TrimSpace(row[5]); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: reserved_at: %s", i+1, err)
+			}
+			c.ReservedAt = &t
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+func writeCases(w io.Writer, cases []Case) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(caseHeader); err != nil {
+		return err
+	}
+	for _, c := range cases {
+		var reservedAt string
+		if c.ReservedAt != nil {
+			reservedAt = c.ReservedAt.Format(time.RFC3339)
+		}
+		err := cw.Write([]string{
+			c.Kind, c.MarketID, c.SteamID, c.Item, strings.Join(c.Personas, "|"), reservedAt, c.Status, c.Confidence,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Inventory file formats.
+const (
+	formatSteam       = "steam"
+	formatPhantasm    = "phantasm"
+	formatSteaminvorg = "steaminvorg"
+)
+
+// fileSource returns an asset source that reads captured raw inventories
+// named by steam id, eg. 76561198088587178.json, the provider id is the
+// detected file format.
+func fileSource(dir string) verify.AssetSource {
+	return func(ctx context.Context, steamID string) (string, []steam.Asset, error) {
+		data, err := os.ReadFile(filepath.Join(dir, steamID+".json"))
+		if err != nil {
+			return "", nil, err
+		}
+
+		format, err := detectFormat(data)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s: %s", steamID, err)
+		}
+		var assets []steam.Asset
+		switch format {
+		case formatSteam:
+			assets, err = steam.ParseInventory(bytes.NewReader(data))
+		case formatPhantasm:
+			assets, err = phantasm.ParseInventory(bytes.NewReader(data))
+		case formatSteaminvorg:
+			assets, err = steam.ParseAllInventory(bytes.NewReader(data))
+		}
+		return format, assets, err
+	}
+}
+
+// detectFormat checks top level keys of raw inventory data.
+func detectFormat(data []byte) (string, error) {
+	var keys map[string]jsoniter.RawMessage
+	if err := fastjson.Unmarshal(data, &keys); err != nil {
+		return "", fmt.Errorf("unmarshal: %s", err)
+	}
+
+	has := func(k string) bool {
+		_, ok := keys[k]
+		return ok
+	}
+	switch {
+	case has("rgInventory"), has("rgDescriptions"), has("Error"):
+		return formatSteam, nil
+	case has("allInventory"), has("allDescriptions"):
+		return formatSteaminvorg, nil
+	case has("assets"), has("descriptions"), has("total_inventory_count"):
+		return formatPhantasm, nil
+	}
+	return "", fmt.Errorf("unknown inventory format")
+}
+
+// replay reruns verification of each case against the source and returns
+// the cases with new status and the changes from the baseline status.
+func replay(ctx context.Context, source verify.AssetSource, cases []Case) ([]Case, []Change) {
+	var results []Case
+	var changes []Change
+	for _, c := range cases {
+		var status fmt.Stringer
+		var confidence int
+		var evidence []dotagiftx.VerifyEvidence
+		item := dotagiftx.Item{Name: c.Item}
+		switch c.Kind {
+		case caseInventory:
+			res, err := verify.Inventory(ctx, source, c.SteamID, item)
+			if err != nil {
+				status = dotagiftx.InventoryStatusError
+				break
+			}
+			status, confidence, evidence = res.Status, res.Confidence, res.Evidence
+		case caseDelivery:
+			res, err := verify.Delivery(ctx, source, c.Personas, c.SteamID, item)
+			if err != nil {
+				status = dotagiftx.DeliveryStatusError
+				break
+			}
+			verify.GiftDate(res, c.Personas, c.ReservedAt)
+			status, confidence, evidence = res.Status, res.Confidence, res.Evidence
+		}
+
+		r := c
+		r.Status = status.String()
+		r.Confidence = fmt.Sprint(confidence)
+		results = append(results, r)
+		if c.Status == "" || c.Status == r.Status {
+			continue
+		}
+
+		ch := Change{Case: c, Status: r.Status, Confidence: confidence}
+		for _, e := range evidence {
+			ch.Evidence = append(ch.Evidence, e.Text)
+		}
+		changes = append(changes, ch)
+	}
+	return results, changes
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func Test_replay(t *testing.T) {
+	f, err := os.Open("testdata/cases.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	cases, err := readCases(f)
+	if err != nil {
+		t.Fatalf("readCases() error = %v", err)
+	}
+
+	results, changes := replay(context.Background(), fileSource("testdata/inventories"), cases)
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Status)
+	}
+	want := []string{"verified", "private", "sender verified", "no hit", "error"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("replay() status = %v, want %v", got, want)
+	}
+	if len(changes) != 1 || changes[0].MarketID != "m4" {
+		t.Errorf("replay() changes = %+v, want m4 only", changes)
+	}
+
+	// Results should be readable as the next baseline.
+	var buf bytes.Buffer
+	if err = writeCases(&buf, results); err != nil {
+		t.Fatalf("writeCases() error = %v", err)
+	}
+	baseline, err := readCases(&buf)
+	if err != nil {
+		t.Fatalf("readCases() baseline error = %v", err)
+	}
+	if _, changes = replay(context.Background(), fileSource("testdata/inventories"), baseline); len(changes) != 0 {
+		t.Errorf("replay() baseline changes = %+v, want none", changes)
+	}
+}
+
+func Test_detectFormat(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{`{"success":true,"rgInventory":{},"rgDescriptions":{}}`, formatSteam, false},
+		{`{"success":false,"Error":"This profile is private."}`, formatSteam, false},
+		{`{"assets":[],"descriptions":[],"total_inventory_count":0}`, formatPhantasm, false},
+		{`{"allInventory":[],"allDescriptions":{}}`, formatSteaminvorg, false},
+		{`{"items":[]}`, "", true},
+		{`[]`, "", true},
+	}
+	for _, tt := range tests {
+		got, err := detectFormat([]byte(tt.data))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("detectFormat(%s) error = %v, wantErr %v", tt.data, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("detectFormat(%s) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 )
@@ -72,6 +73,16 @@ type (
 		SubscriptionEndsAt *time.Time       `json:"subscription_ends_at" db:"subscription_ends_at,omitempty"`
 		Boons              []string         `json:"boons"                db:"boons,omitempty"`
 		Hammer             bool             `json:"hammer"               db:"hammer,omitempty"`
+
+		// Personas are only used for verification and not exposed on user payloads.
+		Personas []UserPersona `json:"-" db:"personas,omitempty"`
+	}
+
+	// UserPersona represents a steam persona name held by the user over a period of time.
+	UserPersona struct {
+		Name        string     `json:"name"          db:"name"`
+		FirstSeenAt *time.Time `json:"first_seen_at" db:"first_seen_at"`
+		LastSeenAt  *time.Time `json:"last_seen_at"  db:"last_seen_at"`
 	}
 
 	ManualSubscriptionParam struct {
@@ -103,6 +114,10 @@ type (
 		// SteamSync saves updated steam info.
 		SteamSync(sp *SteamPlayer) (*User, error)
 
+		// PersonaSync saves steam persona name and profile url changes
+		// without downloading the profile image.
+		PersonaSync(sp *SteamPlayer) (*User, error)
+
 		// ProcessSubscription validates and process subscription features.
 		ProcessSubscription(ctx context.Context, subscriptionID string) (*User, error)
 
@@ -176,6 +191,53 @@ func (u User) CheckStatus() error {
 	return nil
 }
 
+// UserPersonaLeeway is the allowance added around persona history period
+// since delivery time is only an estimate of actual gift date.
+const UserPersonaLeeway = time.Hour * 24 * 7
+
+// RecordPersona tracks persona name changes on the user's persona history.
+func (u *User) RecordPersona(name string, t time.Time) {
+	if name == "" {
+		return
+	}
+	// Seeds history with the current name since it's been held since account creation.
+	if len(u.Personas) == 0 && u.Name != "" {
+		u.Personas = append(u.Personas, UserPersona{u.Name, u.CreatedAt, &t})
+	}
+
+	if n := len(u.Personas); n != 0 && u.Personas[n-1].Name == name {
+		u.Personas[n-1].LastSeenAt = &t
+		return
+	}
+	// Previous persona was held until the change has been detected.
+	if n := len(u.Personas); n != 0 {
+		u.Personas[n-1].LastSeenAt = &t
+	}
+	u.Personas = append(u.Personas, UserPersona{name, &t, &t})
+}
+
+// PersonasAround returns persona names held by the user around the given time,
+// current persona name is always included.
+func (u User) PersonasAround(t time.Time) []string {
+	names := []string{u.Name}
+	for _, p := range u.Personas {
+		if p.Name == u.Name {
+			continue
+		}
+		if p.FirstSeenAt != nil && t.Before(p.FirstSeenAt.Add(-UserPersonaLeeway)) {
+			continue
+		}
+		if p.LastSeenAt != nil && t.After(p.LastSeenAt.Add(UserPersonaLeeway)) {
+			continue
+		}
+		if slices.Contains(names, p.Name) {
+			continue
+		}
+		names = append(names, p.Name)
+	}
+	return names
+}
+
 const (
 	userScoreLiveRate        = 1
 	userScoreReservedRate    = 2
@@ -183,9 +245,10 @@ const (
 	userScoreBidRate         = 1
 	userScoreBidCompleteRate = 4
 
-	userScoreVerifiedInventoryRate      = 2
-	userScoreVerifiedDeliveryNameRate   = 1
-	userScoreVerifiedDeliverySenderRate = 7
+	userScoreVerifiedInventoryRate        = 2
+	userScoreVerifiedDeliveryNameRate     = 1
+	userScoreVerifiedDeliverySenderRate   = 7
+	userScoreVerifiedDeliveryTransferRate = 9
 
 	userScoreResellDeliveryRate = 3
 )
@@ -215,6 +278,7 @@ func (u User) CalcRankScore(stats MarketStatusCount) *User {
 	u.RankScore += (stats.InventoryVerified - stats.ResellLive) * userScoreVerifiedInventoryRate
 	u.RankScore += stats.DeliveryNameVerified * userScoreVerifiedDeliveryNameRate
 	u.RankScore += stats.DeliverySenderVerified * userScoreVerifiedDeliverySenderRate
+	u.RankScore += stats.DeliveryTransferVerified * userScoreVerifiedDeliveryTransferRate
 
 	u.RankScore += stats.ResellSold * userScoreResellDeliveryRate
 	return &u
@@ -351,6 +415,7 @@ func (s *userService) SteamSync(sp *SteamPlayer) (*User, error) {
 		return nil, err
 	}
 
+	u.RecordPersona(sp.Name, time.Now())
 	u.Name = sp.Name
 	u.URL = sp.URL
 	u.Avatar, err = s.downloadProfileImage(sp.Avatar)
@@ -363,6 +428,21 @@ func (s *userService) SteamSync(sp *SteamPlayer) (*User, error) {
 	return u, nil
 }
 
+func (s *userService) PersonaSync(sp *SteamPlayer) (*User, error) {
+	u, err := s.userStg.Get(sp.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.RecordPersona(sp.Name, time.Now())
+	u.Name = sp.Name
+	u.URL = sp.URL
+	if err = s.userStg.Update(u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
 func (s *userService) ProcessSubscription(ctx context.Context, subscriptionID string) (*User, error) {
 	au := AuthFromContext(ctx)
 	if au == nil {
@@ -0,0 +1,96 @@
+package dotagiftx
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUser_PersonasAround(t *testing.T) {
+	day := time.Hour * 24
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	renamed := created.Add(day * 30)
+	renamedAgain := renamed.Add(day * 30)
+
+	u := User{Name: "kudarap", CreatedAt: &created}
+	u.RecordPersona("kudarap", created.Add(day))
+	u.RecordPersona("berserk", renamed)
+	u.Name = "berserk"
+	u.RecordPersona("berserk", renamed.Add(day))
+	u.RecordPersona("sylvan", renamedAgain)
+	u.Name = "sylvan"
+
+	if len(u.Personas) != 3 {
+		t.Fatalf("RecordPersona() history count = %d, want 3", len(u.Personas))
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want []string
+	}{
+		{"first persona", created.Add(day * 2), []string{"sylvan", "kudarap"}},
+		{"during rename", renamed, []string{"sylvan", "kudarap", "berserk"}},
+		{"second persona", renamed.Add(day * 15), []string{"sylvan", "berserk"}},
+		{"current persona", renamedAgain.Add(day * 30), []string{"sylvan"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := u.PersonasAround(tt.at); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PersonasAround() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeUserStorage struct {
+	UserStorage
+	users map[string]User
+}
+
+func (s *fakeUserStorage) Get(id string) (*User, error) {
+	u, ok := s.users[id]
+	if !ok {
+		return nil, UserErrNotFound
+	}
+	return &u, nil
+}
+
+func (s *fakeUserStorage) Update(u *User) error {
+	s.users[u.ID] = *u
+	return nil
+}
+
+func TestUserService_PersonaSync(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	stg := &fakeUserStorage{users: map[string]User{
+		"765": {ID: "765", Name: "kudarap", Avatar: "avatar.jpg", CreatedAt: &created},
+	}}
+	// nil file manager panics when profile image gets downloaded.
+	svc := NewUserService(stg, nil, nil)
+
+	u, err := svc.PersonaSync(&SteamPlayer{ID: "765", Name: "berserk", URL: "https://steamcommunity.com/id/berserk", Avatar: "https://avatars/new.jpg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := stg.users["765"]
+	if saved.Name != "berserk" || saved.URL != "https://steamcommunity.com/id/berserk" || saved.Avatar != "avatar.jpg" {
+		t.Errorf("PersonaSync() saved = %+v, want name and url updated with avatar kept", saved)
+	}
+	if len(u.Personas) != 2 || u.Personas[0].Name != "kudarap" || u.Personas[1].Name != "berserk" {
+		t.Errorf("PersonaSync() personas = %+v, want kudarap then berserk", u.Personas)
+	}
+}
+
+func TestUser_personasHidden(t *testing.T) {
+	u := User{ID: "765", Name: "berserk", Personas: []UserPersona{{Name: "kudarap"}}}
+	b, err := json.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "kudarap") {
+		t.Errorf("User json = %s, want personas hidden", b)
+	}
+}
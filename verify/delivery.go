@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/kudarap/dotagiftx"
 	"github.com/kudarap/dotagiftx/steam"
@@ -14,15 +16,17 @@ import (
 objective: verify a buyer received reserved item from seller
 
 params:
-	- seller persona name: check for sender value
+	- seller persona names: check for sender value, includes previous names
+	  held by the seller around the gift date
 	- buyer steam id: for parsing inventory
-	- item name: item key name to check against sender
+	- item: item name and aliases to check against sender
 
 result:
 	- detect private inventory
 	- detect malformed json
 	- support multiple json for large inventory
 	- challenge check
+	- gift date check against reservation time
 
 process:
 	- download json inventory
@@ -38,29 +42,38 @@ process:
 func Delivery(
 	ctx context.Context,
 	source AssetSource,
-	sellerPersona,
-	buyerSteamID,
-	itemName string,
+	sellerPersonas []string,
+	buyerSteamID string,
+	item dotagiftx.Item,
 ) (*DeliveryResult, error) {
 	result := DeliveryResult{
 		Status: dotagiftx.DeliveryStatusError,
 	}
-	if sellerPersona == "" || buyerSteamID == "" || itemName == "" {
+	sellerPersonas = slices.DeleteFunc(slices.Clone(sellerPersonas), func(s string) bool { return s == "" })
+	if len(sellerPersonas) == 0 || buyerSteamID == "" || item.Name == "" {
 		return &result, fmt.Errorf("all params are required")
 	}
 
 	// Pull inventory data using buyerSteamID.
 	verifier, assets, err := source(ctx, buyerSteamID)
 	result.VerifiedBy = verifier
+	result.Evidence = append(result.Evidence, providerEvidence(verifier))
 	if err != nil {
 		if errors.Is(err, steam.ErrInventoryPrivate) {
 			result.Status = dotagiftx.DeliveryStatusPrivate
+			result.Evidence = append(result.Evidence, privateEvidence())
+			return &result, nil
+		}
+		if errors.Is(err, steam.ErrNotFound) {
+			result.Status = dotagiftx.DeliveryStatusNoHit
+			result.Evidence = append(result.Evidence, notFoundEvidence())
 			return &result, nil
 		}
 		return nil, err
 	}
 
-	assets = filterByName(assets, itemName)
+	result.Evidence = append(result.Evidence, nameEvidence(assets, item, deliveryNameWeight, deliveryAliasWeight)...)
+	assets = filterByName(assets, item)
 	if len(assets) == 0 {
 		result.Status = dotagiftx.DeliveryStatusNoHit
 		return &result, nil
@@ -68,17 +81,121 @@ func Delivery(
 
 	result.Assets = assets
 	result.Status = dotagiftx.DeliveryStatusNameVerified
-	// Check asset sender matches the seller persona name.
+	// Check asset sender matches any of the seller persona names.
 	//
 	// NOTE! checking against seller persona name might not be accurate since
 	// a buyer can clear gift information that's why it need to snapshot
 	// buyer inventory immediately.
+	var sender string
 	for _, ss := range assets {
-		if ss.GiftFrom != sellerPersona {
+		if !slices.Contains(sellerPersonas, ss.GiftFrom) {
 			continue
 		}
 		result.Status = dotagiftx.DeliveryStatusSenderVerified
+		sender = ss.GiftFrom
 	}
-
+	if sender != "" {
+		result.Evidence = append(result.Evidence, dotagiftx.VerifyEvidence{
+			Kind:   dotagiftx.EvidenceSenderMatched,
+			Text:   fmt.Sprintf("sender persona matched: %s", sender),
+			Weight: deliverySenderWeight,
+		})
+	} else {
+		result.Evidence = append(result.Evidence, dotagiftx.VerifyEvidence{
+			Kind: dotagiftx.EvidenceSenderUnmatched,
+			Text: "no gift information from seller personas",
+		})
+	}
+	if e, ok := giftOpenedEvidence(assets); ok {
+		result.Evidence = append(result.Evidence, e)
+	}
+	result.Confidence = dotagiftx.ConfidenceScore(result.Evidence)
 	return &result, nil
 }
+
+// giftOpenedEvidence checks whether the buyer already opened the delivered
+// gifts, assets without gift information could have been traded or cleared
+// and has no evidence.
+func giftOpenedEvidence(a []steam.Asset) (dotagiftx.VerifyEvidence, bool) {
+	if slices.ContainsFunc(a, func(aa steam.Asset) bool { return aa.StillWrapped() }) {
+		return dotagiftx.VerifyEvidence{
+			Kind: dotagiftx.EvidenceGiftWrapped,
+			Text: "gift still wrapped",
+		}, true
+	}
+	if !slices.ContainsFunc(a, hasGiftInfo) {
+		return dotagiftx.VerifyEvidence{}, false
+	}
+	return dotagiftx.VerifyEvidence{
+		Kind:   dotagiftx.EvidenceGiftOpened,
+		Text:   "gift opened",
+		Weight: deliveryGiftOpenedWeight,
+	}, true
+}
+
+func hasGiftInfo(a steam.Asset) bool {
+	return a.GiftFrom != "" || a.DateReceived != "" || a.ReceivedAt != nil
+}
+
+// giftDateLeeway is the allowance for time zone difference on the gift date
+// displayed by steam.
+const giftDateLeeway = time.Hour * 24
+
+// GiftDate flags a sender verified result as backdated when all the assets
+// gifted by the seller were received before the market reservation.
+//
+// Result stays the same when reservation or gift dates are not available.
+func GiftDate(result *DeliveryResult, sellerPersonas []string, reservedAt *time.Time) {
+	if result == nil || result.Status != dotagiftx.DeliveryStatusSenderVerified || reservedAt == nil {
+		return
+	}
+
+	since := reservedAt.Add(-giftDateLeeway)
+	for _, ss := range result.Assets {
+		if ss.GiftFrom == "" || !slices.Contains(sellerPersonas, ss.GiftFrom) {
+			continue
+		}
+		if ss.ReceivedAt == nil {
+			return
+		}
+		if !ss.ReceivedAt.Before(since) {
+			result.addEvidence(dotagiftx.VerifyEvidence{
+				Kind:   dotagiftx.EvidenceGiftDate,
+				Text:   fmt.Sprintf("gift received on %s after reservation", ss.ReceivedAt.Format(time.DateOnly)),
+				Weight: deliveryGiftDateWeight,
+			})
+			return
+		}
+	}
+	result.Status = dotagiftx.DeliveryStatusBackdated
+	result.addEvidence(dotagiftx.VerifyEvidence{
+		Kind:   dotagiftx.EvidenceBackdated,
+		Text:   fmt.Sprintf("gift received before reservation on %s", reservedAt.Format(time.DateOnly)),
+		Weight: deliveryBackdatedWeight,
+	})
+}
+
+// Appeared checks delivered assets against the assets that appeared on the
+// buyer's inventory since reservation, nil appeared assets means there is
+// no inventory snapshot before the reservation to compare with.
+func Appeared(result *DeliveryResult, appeared []steam.Asset) {
+	if result == nil || appeared == nil || len(result.Assets) == 0 {
+		return
+	}
+
+	for _, ss := range result.Assets {
+		if slices.ContainsFunc(appeared, func(a steam.Asset) bool { return a.Name == ss.Name }) {
+			result.addEvidence(dotagiftx.VerifyEvidence{
+				Kind:   dotagiftx.EvidenceAppeared,
+				Text:   fmt.Sprintf("%s appeared on inventory since reservation", ss.Name),
+				Weight: deliveryAppearedWeight,
+			})
+			return
+		}
+	}
+	result.addEvidence(dotagiftx.VerifyEvidence{
+		Kind:   dotagiftx.EvidencePreexisting,
+		Text:   "item was already on inventory before reservation",
+		Weight: deliveryPreexistWeight,
+	})
+}
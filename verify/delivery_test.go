@@ -5,6 +5,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/kudarap/dotagiftx"
 	"github.com/kudarap/dotagiftx/steam"
@@ -59,9 +60,9 @@ func TestVerifyDelivery(t *testing.T) {
 			res, err := Delivery(
 				ctx,
 				steaminvorg.InventoryAssetWithProvider,
-				tt.args.sellerPersona,
+				[]string{tt.args.sellerPersona},
 				tt.args.buyerSteamID,
-				tt.args.itemName)
+				dotagiftx.Item{Name: tt.args.itemName})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Delivery() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -104,16 +105,16 @@ func TestVerifyDeliveryMultiSources(t *testing.T) {
 			res1, err1 := Delivery(
 				ctx,
 				steaminvorg.InventoryAssetWithProvider,
-				tt.args.sellerPersona,
+				[]string{tt.args.sellerPersona},
 				tt.args.buyerSteamID,
-				tt.args.itemName,
+				dotagiftx.Item{Name: tt.args.itemName},
 			)
 			res2, err2 := Delivery(
 				ctx,
 				steam.InventoryAssetWithProvider,
-				tt.args.sellerPersona,
+				[]string{tt.args.sellerPersona},
 				tt.args.buyerSteamID,
-				tt.args.itemName,
+				dotagiftx.Item{Name: tt.args.itemName},
 			)
 
 			if !errors.Is(err2, err1) {
@@ -128,3 +129,109 @@ func TestVerifyDeliveryMultiSources(t *testing.T) {
 		})
 	}
 }
+
+func TestGiftDate(t *testing.T) {
+	reservedAt := time.Date(2024, 4, 11, 0, 0, 0, 0, time.UTC)
+	before := reservedAt.AddDate(0, 0, -3)
+	after := reservedAt.Add(time.Hour)
+	gifted := func(from string, at *time.Time) steam.Asset {
+		return steam.Asset{Name: "Sylvan Vedette", GiftFrom: from, ReceivedAt: at}
+	}
+
+	tests := []struct {
+		name       string
+		status     dotagiftx.DeliveryStatus
+		assets     []steam.Asset
+		reservedAt *time.Time
+		want       dotagiftx.DeliveryStatus
+	}{
+		{"gifted after reservation", dotagiftx.DeliveryStatusSenderVerified,
+			[]steam.Asset{gifted("kudarap", &after)}, &reservedAt, dotagiftx.DeliveryStatusSenderVerified},
+		{"gifted before reservation", dotagiftx.DeliveryStatusSenderVerified,
+			[]steam.Asset{gifted("kudarap", &before)}, &reservedAt, dotagiftx.DeliveryStatusBackdated},
+		{"one of gifts after reservation", dotagiftx.DeliveryStatusSenderVerified,
+			[]steam.Asset{gifted("kudarap", &before), gifted("kudarap", &after)}, &reservedAt,
+			dotagiftx.DeliveryStatusSenderVerified},
+		{"other sender after reservation", dotagiftx.DeliveryStatusSenderVerified,
+			[]steam.Asset{gifted("kudarap", &before), gifted("berserk", &after)}, &reservedAt,
+			dotagiftx.DeliveryStatusBackdated},
+		{"unknown gift date", dotagiftx.DeliveryStatusSenderVerified,
+			[]steam.Asset{gifted("kudarap", nil)}, &reservedAt, dotagiftx.DeliveryStatusSenderVerified},
+		{"unknown reservation", dotagiftx.DeliveryStatusSenderVerified,
+			[]steam.Asset{gifted("kudarap", &before)}, nil, dotagiftx.DeliveryStatusSenderVerified},
+		{"name verified only", dotagiftx.DeliveryStatusNameVerified,
+			[]steam.Asset{gifted("", &before)}, &reservedAt, dotagiftx.DeliveryStatusNameVerified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &DeliveryResult{Status: tt.status, Assets: tt.assets}
+			GiftDate(res, []string{"kudarap"}, tt.reservedAt)
+			if res.Status != tt.want {
+				t.Errorf("GiftDate() status = %v, want %v", res.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppeared(t *testing.T) {
+	delivered := []steam.Asset{{Name: "Sylvan Vedette"}}
+
+	tests := []struct {
+		name     string
+		appeared []steam.Asset
+		want     dotagiftx.EvidenceKind
+	}{
+		{"no snapshot before reservation", nil, ""},
+		{"appeared since reservation", []steam.Asset{{Name: "Sylvan Vedette"}}, dotagiftx.EvidenceAppeared},
+		{"other item appeared", []steam.Asset{{Name: "Fractal Horns of Inner Abysm"}}, dotagiftx.EvidencePreexisting},
+		{"nothing appeared", []steam.Asset{}, dotagiftx.EvidencePreexisting},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &DeliveryResult{Status: dotagiftx.DeliveryStatusNameVerified, Assets: delivered}
+			Appeared(res, tt.appeared)
+			var got dotagiftx.EvidenceKind
+			if len(res.Evidence) != 0 {
+				got = res.Evidence[0].Kind
+			}
+			if got != tt.want {
+				t.Errorf("Appeared() evidence = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type failingSnapshots struct {
+	dotagiftx.InventorySnapshotService
+}
+
+func (failingSnapshots) Record(context.Context, string, []steam.Asset) ([]dotagiftx.InventoryEvent, error) {
+	return nil, nil
+}
+
+func (failingSnapshots) AppearedSince(string, time.Time) ([]steam.Asset, error) {
+	return nil, errors.New("snapshot storage unavailable")
+}
+
+func TestSource_MarketDelivery_appearedError(t *testing.T) {
+	src := NewSource(func(context.Context, string) (string, []steam.Asset, error) {
+		return "test", []steam.Asset{{Name: "Sylvan Vedette", GiftFrom: "kudarap"}}, nil
+	})
+	src.SetSnapshots(failingSnapshots{}, nil)
+
+	reservedAt := time.Now()
+	res, err := src.MarketDelivery(context.Background(), &dotagiftx.Market{
+		PartnerSteamID: "76561198287849998",
+		ReservedAt:     &reservedAt,
+		User:           &dotagiftx.User{Name: "kudarap"},
+		Item:           &dotagiftx.Item{Name: "Sylvan Vedette"},
+	})
+	if err != nil {
+		t.Fatalf("MarketDelivery() error = %v, want snapshot error ignored", err)
+	}
+	for _, e := range res.Evidence {
+		if e.Kind == dotagiftx.EvidenceAppeared || e.Kind == dotagiftx.EvidencePreexisting {
+			t.Errorf("MarketDelivery() evidence = %v, want no appearance evidence", e.Kind)
+		}
+	}
+}
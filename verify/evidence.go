@@ -0,0 +1,142 @@
+package verify
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/steam"
+)
+
+// Evidence weights that adds up to the confidence score of verification result.
+const (
+	inventoryNameWeight      = 60
+	inventoryAliasWeight     = 50
+	inventoryGiftOnceWeight  = 30
+	inventoryImmortalWeight  = 20
+	inventoryQuantityWeight  = 10
+	deliveryNameWeight       = 40
+	deliveryAliasWeight      = 30
+	deliverySenderWeight     = 30
+	deliveryGiftOpenedWeight = 5
+	deliveryGiftDateWeight   = 10
+	deliveryBackdatedWeight  = -40
+	deliveryTransferWeight   = 15
+	deliveryAppearedWeight   = 15
+	deliveryPreexistWeight   = -20
+)
+
+// nameMatch represents how an asset matched the item names.
+type nameMatch int
+
+const (
+	nameMatchNone nameMatch = iota
+	nameMatchDescription
+	nameMatchAlias
+	nameMatchName
+)
+
+func (m nameMatch) String() string {
+	switch m {
+	case nameMatchName:
+		return "name"
+	case nameMatchAlias:
+		return "alias"
+	case nameMatchDescription:
+		return "description"
+	}
+	return "none"
+}
+
+// matchName checks asset name against item name and its aliases, then the
+// asset descriptions that covers wrapped gifts and unbundled items.
+func matchName(asset steam.Asset, names []string) nameMatch {
+	if len(names) == 0 {
+		return nameMatchNone
+	}
+	if strings.Contains(asset.Name, names[0]) {
+		return nameMatchName
+	}
+	if slices.ContainsFunc(names[1:], func(name string) bool {
+		return strings.Contains(asset.Name, name)
+	}) {
+		return nameMatchAlias
+	}
+
+	desc := strings.Join(asset.Descriptions, "|")
+	if slices.ContainsFunc(names, func(name string) bool {
+		return strings.Contains(desc, name)
+	}) {
+		return nameMatchDescription
+	}
+	return nameMatchNone
+}
+
+// nameEvidence explains the best name match and the excluded variants found
+// on the inventory assets.
+func nameEvidence(a []steam.Asset, item dotagiftx.Item, nameWeight, aliasWeight int) []dotagiftx.VerifyEvidence {
+	aliases := item.MatchAliases()
+	names := aliases.MatchNames(item.Name)
+
+	var best nameMatch
+	var evidence []dotagiftx.VerifyEvidence
+	var excluded []string
+	for _, asset := range a {
+		m := matchName(asset, names)
+		if m == nameMatchNone {
+			continue
+		}
+		if aliases.IsExcluded(asset.Name) {
+			if !slices.Contains(excluded, asset.Name) {
+				excluded = append(excluded, asset.Name)
+			}
+			continue
+		}
+		best = max(best, m)
+	}
+
+	for _, name := range excluded {
+		evidence = append(evidence, dotagiftx.VerifyEvidence{
+			Kind: dotagiftx.EvidenceVariantExcluded,
+			Text: fmt.Sprintf("variant excluded: %s", name),
+		})
+	}
+	if best == nameMatchNone {
+		return append(evidence, dotagiftx.VerifyEvidence{
+			Kind: dotagiftx.EvidenceNoHit,
+			Text: fmt.Sprintf("no asset matched %s", item.Name),
+		})
+	}
+
+	weight := aliasWeight
+	if best == nameMatchName {
+		weight = nameWeight
+	}
+	return append(evidence, dotagiftx.VerifyEvidence{
+		Kind:   dotagiftx.EvidenceNameMatched,
+		Text:   fmt.Sprintf("name matched via %s", best),
+		Weight: weight,
+	})
+}
+
+func providerEvidence(provider string) dotagiftx.VerifyEvidence {
+	return dotagiftx.VerifyEvidence{
+		Kind: dotagiftx.EvidenceProvider,
+		Text: fmt.Sprintf("provider: %s", provider),
+	}
+}
+
+func privateEvidence() dotagiftx.VerifyEvidence {
+	return dotagiftx.VerifyEvidence{
+		Kind: dotagiftx.EvidencePrivate,
+		Text: "inventory is private",
+	}
+}
+
+func notFoundEvidence() dotagiftx.VerifyEvidence {
+	return dotagiftx.VerifyEvidence{
+		Kind: dotagiftx.EvidenceNoHit,
+		Text: "steam profile not found",
+	}
+}
@@ -0,0 +1,91 @@
+package verify
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/steam"
+)
+
+func TestDelivery_evidence(t *testing.T) {
+	assets := []steam.Asset{
+		{AssetID: "1", Name: "Dipper the Destroyer", GiftFrom: "kudarap"},
+		{AssetID: "2", Name: "Golden Dipper the Destroyer", GiftFrom: "kudarap"},
+		{AssetID: "4", Name: "Baby Roshan"},
+		{AssetID: "3", Name: "Wrapped Gift", Type: "Rare Mysterious Item", Descriptions: []string{"Contains: Sylvan Vedette"}, GiftFrom: "kudarap"},
+	}
+	source := func(context.Context, string) (string, []steam.Asset, error) {
+		return "phantasm", assets, nil
+	}
+
+	tests := []struct {
+		name           string
+		item           dotagiftx.Item
+		persona        string
+		wantKinds      []dotagiftx.EvidenceKind
+		wantConfidence int
+	}{
+		{"sender verified", dotagiftx.Item{Name: "Dipper the Destroyer Bundle"}, "kudarap", []dotagiftx.EvidenceKind{
+			dotagiftx.EvidenceProvider,
+			dotagiftx.EvidenceVariantExcluded,
+			dotagiftx.EvidenceNameMatched,
+			dotagiftx.EvidenceSenderMatched,
+			dotagiftx.EvidenceGiftOpened,
+		}, 65},
+		{"name verified via description", dotagiftx.Item{Name: "Sylvan Vedette"}, "someone", []dotagiftx.EvidenceKind{
+			dotagiftx.EvidenceProvider,
+			dotagiftx.EvidenceNameMatched,
+			dotagiftx.EvidenceSenderUnmatched,
+			dotagiftx.EvidenceGiftWrapped,
+		}, 30},
+		{"name verified without gift info", dotagiftx.Item{Name: "Baby Roshan"}, "kudarap", []dotagiftx.EvidenceKind{
+			dotagiftx.EvidenceProvider,
+			dotagiftx.EvidenceNameMatched,
+			dotagiftx.EvidenceSenderUnmatched,
+		}, 40},
+		{"no hit", dotagiftx.Item{Name: "Baby Demon"}, "kudarap", []dotagiftx.EvidenceKind{
+			dotagiftx.EvidenceProvider,
+			dotagiftx.EvidenceNoHit,
+		}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Delivery(context.Background(), source, []string{tt.persona}, "76561198287849998", tt.item)
+			if err != nil {
+				t.Fatalf("Delivery() error = %v", err)
+			}
+			var kinds []dotagiftx.EvidenceKind
+			for _, e := range res.Evidence {
+				kinds = append(kinds, e.Kind)
+			}
+			if !reflect.DeepEqual(kinds, tt.wantKinds) {
+				t.Errorf("Delivery() evidence = %v, want %v", kinds, tt.wantKinds)
+			}
+			if res.Confidence != tt.wantConfidence {
+				t.Errorf("Delivery() confidence = %v, want %v", res.Confidence, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestConfidenceScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		evidence []dotagiftx.VerifyEvidence
+		want     int
+	}{
+		{"empty", nil, 0},
+		{"sum", []dotagiftx.VerifyEvidence{{Weight: 40}, {Weight: 30}}, 70},
+		{"below zero", []dotagiftx.VerifyEvidence{{Weight: 10}, {Weight: deliveryBackdatedWeight}}, 0},
+		{"above hundred", []dotagiftx.VerifyEvidence{{Weight: 80}, {Weight: 60}}, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dotagiftx.ConfidenceScore(tt.evidence); got != tt.want {
+				t.Errorf("ConfidenceScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -28,7 +28,10 @@ func main() {
 		panic(err)
 	}
 
-	phantasmSvc := phantasm.NewService(conf.Phantasm, redisClient, slog.Default())
+	phantasmSvc, err := phantasm.NewService(conf.Phantasm, redisClient, slog.Default())
+	if err != nil {
+		panic(err)
+	}
 	assetSrc := verify.JoinAssetSource(
 		phantasmSvc.InventoryAssetWithProvider,
 		steaminvorg.InventoryAssetWithProvider,
@@ -46,7 +49,7 @@ func main() {
 
 	ctx := context.Background()
 	for _, item := range items {
-		result, err := verify.Delivery(ctx, assetSrc, item.User.Name, item.PartnerSteamID, item.Item.Name)
+		result, err := verify.Delivery(ctx, assetSrc, item.SellerPersonas(), item.PartnerSteamID, *item.Item)
 		fmt.Println(strings.Repeat("-", 70))
 		fmt.Printf("%s -> %s (%s)\n", item.User.Name, item.PartnerSteamID, item.Item.Name)
 		fmt.Println(strings.Repeat("-", 70))
@@ -57,6 +60,10 @@ func main() {
 		}
 		fmt.Println("Verified by:", result.VerifiedBy)
 		fmt.Println("Status:", result.Status)
+		fmt.Println("Confidence:", result.Confidence)
+		for _, e := range result.Evidence {
+			fmt.Println("Evidence:", e.Text)
+		}
 
 		okCtr++
 
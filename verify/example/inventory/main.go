@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"strings"
 
+	"github.com/kudarap/dotagiftx"
 	"github.com/kudarap/dotagiftx/config"
 	"github.com/kudarap/dotagiftx/phantasm"
 	"github.com/kudarap/dotagiftx/redis"
@@ -26,7 +27,10 @@ func main() {
 	logger := slog.Default()
 	slog.SetLogLoggerLevel(slog.LevelDebug)
 
-	phantasmSvc := phantasm.NewService(conf.Phantasm, redisClient, logger)
+	phantasmSvc, err := phantasm.NewService(conf.Phantasm, redisClient, logger)
+	if err != nil {
+		panic(err)
+	}
 	assetSrc := verify.JoinAssetSource(
 		phantasmSvc.InventoryAssetWithProvider,
 		steaminvorg.InventoryAssetWithProvider,
@@ -42,7 +46,7 @@ func main() {
 
 	ctx := context.Background()
 	for _, param := range params {
-		result, err := verify.Inventory(ctx, assetSrc, param.steamID, param.item)
+		result, err := verify.Inventory(ctx, assetSrc, param.steamID, dotagiftx.Item{Name: param.item})
 		fmt.Println(strings.Repeat("-", 70))
 		fmt.Printf("%s -> %s\n", param.steamID, param.item)
 		fmt.Println(strings.Repeat("-", 70))
@@ -54,6 +58,10 @@ func main() {
 		snaps := result.Assets
 		fmt.Println("Verified by:", result.VerifiedBy)
 		fmt.Println("Status:", result.Status)
+		fmt.Println("Confidence:", result.Confidence)
+		for _, e := range result.Evidence {
+			fmt.Println("Evidence:", e.Text)
+		}
 		fmt.Println("Items:", len(snaps))
 		if len(snaps) == 0 {
 			fmt.Println("")
@@ -0,0 +1,358 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/steam"
+)
+
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = time.Minute
+
+	// healthWeight is the weight of the latest sample on moving averages.
+	healthWeight = 0.2
+
+	// StatsCacheKey is where the worker publishes provider stats for operators.
+	StatsCacheKey = "verify_provider_stats"
+)
+
+// Config represents asset source joining config.
+type Config struct {
+	// HedgeDelay fires the next provider when the current one has not
+	// answered yet after the delay, zero value disables hedging. Private
+	// inventory answers are only confirmed by another provider in hedged
+	// mode, sequential mode trusts the first private answer.
+	HedgeDelay time.Duration `envconfig:"HEDGE_DELAY"`
+	// BreakerThreshold consecutive provider failures that opens the circuit.
+	BreakerThreshold int `envconfig:"BREAKER_THRESHOLD"`
+	// BreakerCooldown duration of skipping provider when circuit is open.
+	BreakerCooldown time.Duration `envconfig:"BREAKER_COOLDOWN"`
+	// Providers are registered inventory provider names in order they are
+	// tried with optional weight in "name:weight" format. Weighted providers
+	// share the first try by weighted random and the rest follows the order.
+	Providers []string
+}
+
+func (c Config) setDefaults() Config {
+	if c.BreakerThreshold <= 0 {
+		c.BreakerThreshold = defaultBreakerThreshold
+	}
+	if c.BreakerCooldown <= 0 {
+		c.BreakerCooldown = defaultBreakerCooldown
+	}
+	return c
+}
+
+// ProviderStats represents asset source provider health.
+type ProviderStats struct {
+	Name      string        `json:"name"`
+	Requests  int           `json:"requests"`
+	Errors    int           `json:"errors"`
+	ErrorRate float64       `json:"error_rate"`
+	Latency   time.Duration `json:"latency"`
+	// PrivateChecks number of private inventory answers confirmed by another
+	// provider and PrivateAgreed is how many of them agreed.
+	PrivateChecks int        `json:"private_checks"`
+	PrivateAgreed int        `json:"private_agreed"`
+	Open          bool       `json:"open"`
+	OpenUntil     *time.Time `json:"open_until,omitempty"`
+	Weight        int        `json:"weight"`
+	// Capabilities and HealthError are only available on registered providers.
+	Capabilities *dotagiftx.ProviderCapabilities `json:"capabilities,omitempty"`
+	HealthError  string                          `json:"health_error,omitempty"`
+}
+
+// providerHealth tracks provider stats and its circuit breaker state.
+type providerHealth struct {
+	source   AssetSource
+	provider dotagiftx.InventoryProvider
+	weight   int
+
+	mu        sync.Mutex
+	stats     ProviderStats
+	failures  int
+	openUntil time.Time
+}
+
+// allow checks whether the provider circuit is closed. When cooldown is over
+// the circuit is half-open, a success closes it and a failure re-opens it
+// since consecutive failures are still over the threshold.
+func (h *providerHealth) allow(now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return !now.Before(h.openUntil)
+}
+
+func (h *providerHealth) record(c Config, name string, latency time.Duration, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if name != "" {
+		h.stats.Name = name
+	}
+	h.stats.Requests++
+	if h.stats.Latency == 0 {
+		h.stats.Latency = latency
+	} else {
+		h.stats.Latency += time.Duration(healthWeight * float64(latency-h.stats.Latency))
+	}
+
+	// Private and not found inventory are valid answers and not a provider failure.
+	failed := err != nil && !isAnswer(err)
+	sample := 0.0
+	if failed {
+		sample = 1
+	}
+	h.stats.ErrorRate += healthWeight * (sample - h.stats.ErrorRate)
+	if !failed {
+		h.failures = 0
+		h.openUntil = time.Time{}
+		return
+	}
+
+	h.stats.Errors++
+	h.failures++
+	if h.failures >= c.BreakerThreshold {
+		h.openUntil = time.Now().Add(c.BreakerCooldown)
+	}
+
+	// Rate limited provider should not be used until the time it suggested.
+	var rle *steam.RateLimitError
+	if errors.As(err, &rle) && rle.RetryAfter > 0 {
+		if until := time.Now().Add(rle.RetryAfter); until.After(h.openUntil) {
+			h.openUntil = until
+		}
+	}
+}
+
+// isAnswer checks provider error that is a valid answer about the inventory.
+func isAnswer(err error) bool {
+	return errors.Is(err, steam.ErrInventoryPrivate) || errors.Is(err, steam.ErrNotFound)
+}
+
+// recordHealth opens the circuit of unhealthy provider for the cooldown,
+// healthy check does not close a circuit opened by failed requests.
+func (h *providerHealth) recordHealth(c Config, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stats.HealthError = ""
+	if err == nil {
+		return
+	}
+	h.stats.HealthError = err.Error()
+	if until := time.Now().Add(c.BreakerCooldown); until.After(h.openUntil) {
+		h.openUntil = until
+	}
+}
+
+func (h *providerHealth) recordPrivate(agreed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stats.PrivateChecks++
+	if agreed {
+		h.stats.PrivateAgreed++
+	}
+}
+
+func (h *providerHealth) snapshot(now time.Time) ProviderStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.stats
+	if now.Before(h.openUntil) {
+		t := h.openUntil
+		s.Open = true
+		s.OpenUntil = &t
+	}
+	return s
+}
+
+// joinedSource joins asset source providers with health tracking, circuit
+// breaker and optional hedged requests.
+type joinedSource struct {
+	config    Config
+	providers []*providerHealth
+}
+
+func newJoinedSource(c Config, providers ...AssetSource) *joinedSource {
+	j := &joinedSource{config: c.setDefaults()}
+	for i, p := range providers {
+		h := &providerHealth{source: p}
+		h.stats.Name = fmt.Sprintf("provider-%d", i)
+		j.providers = append(j.providers, h)
+	}
+	return j
+}
+
+// addProvider joins a registered inventory provider.
+func (j *joinedSource) addProvider(p dotagiftx.InventoryProvider, weight int) {
+	name := p.Name()
+	capabilities := p.Capabilities()
+	h := &providerHealth{
+		source: func(ctx context.Context, steamID string) (string, []steam.Asset, error) {
+			assets, err := p.InventoryAsset(ctx, steamID)
+			return name, assets, err
+		},
+		provider: p,
+		weight:   weight,
+	}
+	h.stats.Name = name
+	h.stats.Weight = weight
+	h.stats.Capabilities = &capabilities
+	j.providers = append(j.providers, h)
+}
+
+// weightedFirst moves a provider picked by weighted random to the front,
+// providers without weight are only tried after it in order.
+func weightedFirst(providers []*providerHealth) []*providerHealth {
+	var total int
+	for _, p := range providers {
+		total += p.weight
+	}
+	if total == 0 {
+		return providers
+	}
+
+	n := rand.IntN(total)
+	for i, p := range providers {
+		if n -= p.weight; n < 0 {
+			ordered := append([]*providerHealth{p}, providers[:i]...)
+			return append(ordered, providers[i+1:]...)
+		}
+	}
+	return providers
+}
+
+func (j *joinedSource) stats() []ProviderStats {
+	now := time.Now()
+	var s []ProviderStats
+	for _, p := range j.providers {
+		s = append(s, p.snapshot(now))
+	}
+	return s
+}
+
+// assetSource skips providers with open circuit and uses hedged requests when enabled.
+func (j *joinedSource) assetSource(ctx context.Context, steamID string) (string, []steam.Asset, error) {
+	now := time.Now()
+	var healthy []*providerHealth
+	for _, p := range j.providers {
+		if p.allow(now) {
+			healthy = append(healthy, p)
+		}
+	}
+	if len(healthy) == 0 {
+		return "", nil, fmt.Errorf("all source circuit open: %s", steamID)
+	}
+	healthy = weightedFirst(healthy)
+
+	if j.config.HedgeDelay > 0 {
+		return j.hedged(ctx, steamID, healthy)
+	}
+	return j.sequential(ctx, steamID, healthy)
+}
+
+func (j *joinedSource) sequential(ctx context.Context, steamID string, providers []*providerHealth) (string, []steam.Asset, error) {
+	for _, p := range providers {
+		start := time.Now()
+		name, assets, err := p.source(ctx, steamID)
+		p.record(j.config, name, time.Since(start), err)
+		if err != nil {
+			if isAnswer(err) {
+				return name, nil, err
+			}
+			continue
+		}
+		return name, assets, nil
+	}
+	return "", nil, fmt.Errorf("all source exhausted: %s", steamID)
+}
+
+type providerAnswer struct {
+	provider *providerHealth
+	name     string
+	assets   []steam.Asset
+	err      error
+}
+
+// hedged fires the next provider when the current one is slow or failed and
+// the first valid answer wins. Private inventory answer waits for another
+// provider to confirm it when available.
+func (j *joinedSource) hedged(ctx context.Context, steamID string, providers []*providerHealth) (string, []steam.Asset, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	answers := make(chan providerAnswer, len(providers))
+	next, inflight := 0, 0
+	launch := func() {
+		p := providers[next]
+		next++
+		inflight++
+		go func() {
+			start := time.Now()
+			name, assets, err := p.source(ctx, steamID)
+			// Cancelled requests are lost hedges and not the provider's fault.
+			if err == nil || ctx.Err() == nil {
+				p.record(j.config, name, time.Since(start), err)
+			}
+			answers <- providerAnswer{p, name, assets, err}
+		}()
+	}
+
+	timer := time.NewTimer(j.config.HedgeDelay)
+	defer timer.Stop()
+	launch()
+
+	var private *providerAnswer
+	for inflight > 0 {
+		select {
+		case <-ctx.Done():
+			return "", nil, ctx.Err()
+		case <-timer.C:
+			if next < len(providers) {
+				launch()
+				timer.Reset(j.config.HedgeDelay)
+			}
+			continue
+		case a := <-answers:
+			inflight--
+			switch {
+			case a.err == nil:
+				if private != nil {
+					private.provider.recordPrivate(false)
+				}
+				return a.name, a.assets, nil
+			case errors.Is(a.err, steam.ErrNotFound):
+				return a.name, nil, a.err
+			case errors.Is(a.err, steam.ErrInventoryPrivate):
+				if private != nil {
+					private.provider.recordPrivate(true)
+					a.provider.recordPrivate(true)
+					return a.name, nil, a.err
+				}
+				private = &a
+			}
+		}
+
+		// Fires next provider right away on failure or unconfirmed private answer.
+		if next < len(providers) {
+			launch()
+			timer.Reset(j.config.HedgeDelay)
+		}
+	}
+
+	if private != nil {
+		return private.name, nil, private.err
+	}
+	return "", nil, fmt.Errorf("all source exhausted: %s", steamID)
+}
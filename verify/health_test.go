@@ -0,0 +1,98 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kudarap/dotagiftx/steam"
+)
+
+func testProvider(name string, delay time.Duration, assets []steam.Asset, err error) AssetSource {
+	return func(ctx context.Context, _ string) (string, []steam.Asset, error) {
+		select {
+		case <-ctx.Done():
+			return name, nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		return name, assets, err
+	}
+}
+
+func TestJoinedSource_breaker(t *testing.T) {
+	failing := testProvider("failing", 0, nil, errors.New("bad gateway"))
+	ok := testProvider("ok", 0, []steam.Asset{{AssetID: "1"}}, nil)
+	j := newJoinedSource(Config{BreakerThreshold: 2, BreakerCooldown: time.Hour}, failing, ok)
+
+	for i := 0; i < 3; i++ {
+		name, _, err := j.assetSource(context.Background(), "1")
+		if err != nil || name != "ok" {
+			t.Fatalf("assetSource() = %s, %v, want ok answer", name, err)
+		}
+	}
+
+	stats := j.stats()
+	if !stats[0].Open {
+		t.Errorf("stats() failing provider should be open")
+	}
+	if stats[0].Requests != 2 {
+		t.Errorf("stats() failing provider requests = %d, want 2 before circuit opens", stats[0].Requests)
+	}
+	if stats[1].Open || stats[1].Requests != 3 {
+		t.Errorf("stats() ok provider = %+v, want closed with 3 requests", stats[1])
+	}
+}
+
+func TestJoinedSource_hedged(t *testing.T) {
+	slow := testProvider("slow", time.Second, []steam.Asset{{AssetID: "1"}}, nil)
+	fast := testProvider("fast", 0, []steam.Asset{{AssetID: "2"}}, nil)
+	private := testProvider("private", 0, nil, steam.ErrInventoryPrivate)
+
+	tests := []struct {
+		name      string
+		providers []AssetSource
+		want      string
+		wantErr   error
+	}{
+		{"fast wins over slow", []AssetSource{slow, fast}, "fast", nil},
+		{"assets wins over private", []AssetSource{private, fast}, "fast", nil},
+		{"confirmed private", []AssetSource{private, private}, "private", steam.ErrInventoryPrivate},
+		{"unconfirmed private", []AssetSource{private}, "private", steam.ErrInventoryPrivate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := newJoinedSource(Config{HedgeDelay: time.Millisecond * 10}, tt.providers...)
+			start := time.Now()
+			name, _, err := j.assetSource(context.Background(), "1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("assetSource() error = %v, want %v", err, tt.wantErr)
+			}
+			if name != tt.want {
+				t.Errorf("assetSource() name = %s, want %s", name, tt.want)
+			}
+			if time.Since(start) > time.Second/2 {
+				t.Errorf("assetSource() took %s, hedged request should not wait for slow provider", time.Since(start))
+			}
+		})
+	}
+}
+
+func TestJoinedSource_rateLimited(t *testing.T) {
+	limited := testProvider("limited", 0, nil, &steam.RateLimitError{RetryAfter: time.Hour})
+	notFound := testProvider("not found", 0, nil, steam.ErrNotFound)
+	j := newJoinedSource(Config{}, limited, notFound)
+
+	name, _, err := j.assetSource(context.Background(), "1")
+	if !errors.Is(err, steam.ErrNotFound) || name != "not found" {
+		t.Fatalf("assetSource() = %s, %v, want not found answer", name, err)
+	}
+
+	stats := j.stats()
+	if !stats[0].Open {
+		t.Errorf("stats() rate limited provider should be open until retry after")
+	}
+	if stats[1].Open || stats[1].Errors != 0 {
+		t.Errorf("stats() not found provider = %+v, want answer without error", stats[1])
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
+	"slices"
 
 	"github.com/kudarap/dotagiftx"
 	"github.com/kudarap/dotagiftx/steam"
@@ -13,53 +13,90 @@ import (
 // Inventory checks item existence on inventory.
 //
 // Returns an error when request has status error or response body malformed.
-func Inventory(ctx context.Context, source AssetSource, steamID, itemName string) (*InventorResult, error) {
+func Inventory(ctx context.Context, source AssetSource, steamID string, item dotagiftx.Item) (*InventorResult, error) {
 	result := InventorResult{
 		Status: dotagiftx.InventoryStatusError,
 	}
-	if steamID == "" || itemName == "" {
+	if steamID == "" || item.Name == "" {
 		return &result, fmt.Errorf("all params are required")
 	}
 
 	verifier, assets, err := source(ctx, steamID)
 	result.VerifiedBy = verifier
+	result.Evidence = append(result.Evidence, providerEvidence(verifier))
 	if err != nil {
 		if errors.Is(err, steam.ErrInventoryPrivate) {
 			result.Status = dotagiftx.InventoryStatusPrivate
+			result.Evidence = append(result.Evidence, privateEvidence())
+			return &result, nil
+		}
+		if errors.Is(err, steam.ErrNotFound) {
+			result.Status = dotagiftx.InventoryStatusNoHit
+			result.Evidence = append(result.Evidence, notFoundEvidence())
 			return &result, nil
 		}
 		return nil, err
 	}
 
-	assets = filterByName(assets, itemName)
+	result.Evidence = append(result.Evidence, nameEvidence(assets, item, inventoryNameWeight, inventoryAliasWeight)...)
+	assets = filterByName(assets, item)
+	named := len(assets)
 	assets = filterByGiftable(assets)
 	if len(assets) == 0 {
+		if named != 0 {
+			result.Evidence = append(result.Evidence, dotagiftx.VerifyEvidence{
+				Kind: dotagiftx.EvidenceNotGiftable,
+				Text: "matched assets are not giftable",
+			})
+		}
 		result.Status = dotagiftx.InventoryStatusNoHit
 		return &result, nil
 	}
 
 	result.Assets = assets
 	result.Status = dotagiftx.InventoryStatusVerified
+	result.Evidence = append(result.Evidence, giftableEvidence(assets), dotagiftx.VerifyEvidence{
+		Kind:   dotagiftx.EvidenceQuantity,
+		Text:   fmt.Sprintf("owns %d matching assets", len(assets)),
+		Weight: inventoryQuantityWeight,
+	})
+	result.Confidence = dotagiftx.ConfidenceScore(result.Evidence)
 	return &result, nil
 }
 
+// giftableEvidence prefers assets with explicit gift once description over
+// immortal assets assumed to be giftable.
+func giftableEvidence(a []steam.Asset) dotagiftx.VerifyEvidence {
+	if slices.ContainsFunc(a, func(aa steam.Asset) bool { return aa.GiftOnce }) {
+		return dotagiftx.VerifyEvidence{
+			Kind:   dotagiftx.EvidenceGiftable,
+			Text:   "giftable via gift once description",
+			Weight: inventoryGiftOnceWeight,
+		}
+	}
+	return dotagiftx.VerifyEvidence{
+		Kind:   dotagiftx.EvidenceGiftable,
+		Text:   "giftable as immortal item",
+		Weight: inventoryImmortalWeight,
+	}
+}
+
 // filterByName filters item that matches the name or in the description that supports unbundled items.
-func filterByName(a []steam.Asset, name string) []steam.Asset {
+//
+// Item aliases covers alternate names, misspellings, bundle components and
+// excluded variants of the item.
+func filterByName(a []steam.Asset, item dotagiftx.Item) []steam.Asset {
+	aliases := item.MatchAliases()
+	names := aliases.MatchNames(item.Name)
+
 	var matches []steam.Asset
 	for _, asset := range a {
-		// Strip "bundle" suffix to cover unbundled items:
-		// - Dipper the Destroyer Bundle
-		// - The Abscesserator Bundle
-		name = strings.TrimSpace(strings.TrimSuffix(name, "Bundle"))
-		// Fix misspelled names like earth shakers arcana.
-		asset.Name = fixMisspelledName(asset.Name, name)
-		desc := fixMisspelledName(strings.Join(asset.Descriptions, "|"), name)
-		if !strings.Contains(desc, name) && !strings.Contains(asset.Name, name) {
+		if matchName(asset, names) == nameMatchNone {
 			continue
 		}
 
-		// Excluded golden variant of the item.
-		if asset.IsGoldenVariant(name) {
+		// Excluded variants of the item like golden.
+		if aliases.IsExcluded(asset.Name) {
 			continue
 		}
 
@@ -85,16 +122,3 @@ func filterByGiftable(a []steam.Asset) []steam.Asset {
 	}
 	return matches
 }
-
-func fixMisspelledName(a, b string) string {
-	if strings.EqualFold(a, b) {
-		return a
-	}
-	if strings.EqualFold(a, "Intergalactic Orbliterator") {
-		return "Intergalactic Obliterator"
-	}
-	if strings.Contains(a, "Orbliterator") && strings.Contains(b, "Obliterator") {
-		return strings.ReplaceAll(a, "Orbliterator", "Obliterator")
-	}
-	return a
-}
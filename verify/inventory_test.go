@@ -2,9 +2,11 @@ package verify
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/steam"
 )
 
 func TestVerifyInventory(t *testing.T) {
@@ -56,7 +58,7 @@ func TestVerifyInventory(t *testing.T) {
 
 			ctx := context.Background()
 			src := JoinAssetSource()
-			res, err := Inventory(ctx, src, tt.steamID, tt.itemName)
+			res, err := Inventory(ctx, src, tt.steamID, dotagiftx.Item{Name: tt.itemName})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Inventory() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -73,41 +75,42 @@ func TestVerifyInventory(t *testing.T) {
 	}
 }
 
-func Test_fixMisspelledName(t *testing.T) {
+func Test_filterByName(t *testing.T) {
+	assets := []steam.Asset{
+		{AssetID: "1", Name: "Intergalactic Orbliterator"},
+		{AssetID: "2", Name: "Intergalactic Orbliterator - Head"},
+		{AssetID: "3", Name: "Dipper the Destroyer"},
+		{AssetID: "4", Name: "Golden Dipper the Destroyer"},
+		{AssetID: "5", Name: "Chrononaut Continuum"},
+		{AssetID: "6", Name: "Wrapped Gift", Descriptions: []string{"Contains: Sylvan Vedette"}},
+		{AssetID: "7", Name: "Vedette of the Sylvan"},
+	}
 	tests := []struct {
 		name string
-		a, b string
-		want string
+		item dotagiftx.Item
+		want []string
 	}{
-		{
-			"backward compatible",
-			"Intergalactic Orbliterator",
-			"Intergalactic Orbliterator",
-			"Intergalactic Orbliterator",
-		},
-		{
-			"fix",
-			"Intergalactic Orbliterator",
-			"Intergalactic Obliterator",
-			"Intergalactic Obliterator",
-		},
-		{
-			"fix parts",
-			"Intergalactic Orbliterator - Head",
-			"Intergalactic Obliterator - Head",
-			"Intergalactic Obliterator - Head",
-		},
-		{
-			"skip fix",
-			"Chrononaut Continuum",
-			"Intergalactic Obliterator",
-			"Chrononaut Continuum",
-		},
+		{"default misspelling", dotagiftx.Item{Name: "Intergalactic Obliterator"}, []string{"1", "2"}},
+		{"unbundled and excludes golden", dotagiftx.Item{Name: "Dipper the Destroyer Bundle"}, []string{"3"}},
+		{"exact name", dotagiftx.Item{Name: "Chrononaut Continuum"}, []string{"5"}},
+		{"name in description", dotagiftx.Item{Name: "Sylvan Vedette"}, []string{"6"}},
+		{"alternate name", dotagiftx.Item{
+			Name:    "Sylvan Vedette",
+			Aliases: &dotagiftx.ItemAliases{Names: []string{"Vedette of the Sylvan"}},
+		}, []string{"6", "7"}},
+		{"excluded variant", dotagiftx.Item{
+			Name:    "Chrononaut Continuum",
+			Aliases: &dotagiftx.ItemAliases{Excludes: []string{"Chrononaut Continuum"}},
+		}, nil},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := fixMisspelledName(tt.a, tt.b); got != tt.want {
-				t.Errorf("fixMisspelledName() = %v, want %v", got, tt.want)
+			var got []string
+			for _, a := range filterByName(assets, tt.item) {
+				got = append(got, a.AssetID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterByName() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,94 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/logging"
+)
+
+// defaultProviders are used when config has no providers.
+var defaultProviders = []string{"phantasm", "steaminvorg"}
+
+// NewProviderSource creates a Source from registered inventory providers
+// set on config with their order and weight, providers are created from
+// their config section and dependencies on the setup.
+func NewProviderSource(c Config, s dotagiftx.InventoryProviderSetup) (*Source, error) {
+	return newProviderSource(c, func(name string) (dotagiftx.InventoryProvider, error) {
+		return dotagiftx.NewInventoryProvider(name, s)
+	})
+}
+
+func newProviderSource(c Config, create func(name string) (dotagiftx.InventoryProvider, error)) (*Source, error) {
+	specs := c.Providers
+	if len(specs) == 0 {
+		specs = defaultProviders
+	}
+
+	j := newJoinedSource(c)
+	seen := map[string]bool{}
+	for _, spec := range specs {
+		name, weight, err := parseProviderSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("inventory provider %s is set twice", name)
+		}
+		seen[name] = true
+
+		p, err := create(name)
+		if err != nil {
+			return nil, err
+		}
+		j.addProvider(p, weight)
+	}
+	return &Source{joined: j, logger: logging.DefaultWithPrefix(logPrefix)}, nil
+}
+
+// parseProviderSpec parses provider in "name:weight" format, weight is
+// optional and defaults to zero.
+func parseProviderSpec(spec string) (name string, weight int, err error) {
+	name, w, hasWeight := strings.Cut(strings.TrimSpace(spec), ":")
+	if name == "" {
+		return "", 0, fmt.Errorf("invalid inventory provider %q", spec)
+	}
+	if !hasWeight {
+		return name, 0, nil
+	}
+	weight, err = strconv.Atoi(w)
+	if err != nil || weight < 0 {
+		return "", 0, fmt.Errorf("invalid inventory provider weight %q", spec)
+	}
+	return name, weight, nil
+}
+
+// Invalidate drops cached inventory of steam id on providers with cache so
+// the next verification gets a fresh inventory.
+func (s *Source) Invalidate(ctx context.Context, steamID string) error {
+	var errs []error
+	for _, p := range s.joined.providers {
+		if p.provider == nil || !p.provider.Capabilities().Cached {
+			continue
+		}
+		if err := p.provider.Invalidate(ctx, steamID); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.provider.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckHealth runs health check of registered providers, unhealthy provider
+// circuit opens for the breaker cooldown.
+func (s *Source) CheckHealth(ctx context.Context) {
+	for _, p := range s.joined.providers {
+		if p.provider == nil {
+			continue
+		}
+		p.recordHealth(s.joined.config, p.provider.Health(ctx))
+	}
+}
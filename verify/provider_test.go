@@ -0,0 +1,136 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/steam"
+)
+
+type testInventoryProvider struct {
+	name        string
+	cached      bool
+	health      error
+	invalidated []string
+}
+
+func (p *testInventoryProvider) Name() string { return p.name }
+
+func (p *testInventoryProvider) Capabilities() dotagiftx.ProviderCapabilities {
+	return dotagiftx.ProviderCapabilities{Cached: p.cached}
+}
+
+func (p *testInventoryProvider) InventoryAsset(context.Context, string) ([]steam.Asset, error) {
+	return []steam.Asset{{AssetID: p.name}}, nil
+}
+
+func (p *testInventoryProvider) Invalidate(_ context.Context, steamID string) error {
+	p.invalidated = append(p.invalidated, steamID)
+	return nil
+}
+
+func (p *testInventoryProvider) Health(context.Context) error { return p.health }
+
+func testLookup(providers ...*testInventoryProvider) func(string) (dotagiftx.InventoryProvider, error) {
+	return func(name string) (dotagiftx.InventoryProvider, error) {
+		for _, p := range providers {
+			if p.name == name {
+				return p, nil
+			}
+		}
+		return nil, fmt.Errorf("inventory provider %s is not registered", name)
+	}
+}
+
+func TestNewProviderSource(t *testing.T) {
+	lookup := testLookup(&testInventoryProvider{name: "a"}, &testInventoryProvider{name: "b"})
+	tests := []struct {
+		name      string
+		providers []string
+		want      []string
+		wantErr   bool
+	}{
+		{"config order", []string{"b", "a:2"}, []string{"b", "a"}, false},
+		{"not registered", []string{"a", "c"}, nil, true},
+		{"set twice", []string{"a", "a:1"}, nil, true},
+		{"bad weight", []string{"a:heavy"}, nil, true},
+		{"negative weight", []string{"a:-1"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := newProviderSource(Config{Providers: tt.providers}, lookup)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newProviderSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, s := range src.Stats() {
+				got = append(got, s.Name)
+			}
+			if len(got) != len(tt.want) || got[0] != tt.want[0] || got[1] != tt.want[1] {
+				t.Errorf("newProviderSource() providers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSource_weightedProviders(t *testing.T) {
+	lookup := testLookup(
+		&testInventoryProvider{name: "fallback"},
+		&testInventoryProvider{name: "heavy"},
+		&testInventoryProvider{name: "light"},
+	)
+	src, err := newProviderSource(Config{Providers: []string{"fallback", "heavy:3", "light:1"}}, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	picks := map[string]int{}
+	for range 400 {
+		name, _, err := src.joined.assetSource(context.Background(), "1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		picks[name]++
+	}
+	if picks["fallback"] != 0 {
+		t.Errorf("unweighted provider picked first %d times, want 0", picks["fallback"])
+	}
+	if picks["heavy"] <= picks["light"] {
+		t.Errorf("weighted picks heavy:%d light:%d, want heavy favored", picks["heavy"], picks["light"])
+	}
+}
+
+func TestSource_invalidateAndHealth(t *testing.T) {
+	cached := &testInventoryProvider{name: "cached", cached: true, health: errors.New("fleet quarantined")}
+	live := &testInventoryProvider{name: "live"}
+	src, err := newProviderSource(Config{Providers: []string{"cached", "live"}}, testLookup(cached, live))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = src.Invalidate(context.Background(), "1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(cached.invalidated) != 1 || len(live.invalidated) != 0 {
+		t.Errorf("Invalidate() cached:%v live:%v, want only cached provider", cached.invalidated, live.invalidated)
+	}
+
+	src.CheckHealth(context.Background())
+	stats := src.Stats()
+	if !stats[0].Open || stats[0].HealthError == "" {
+		t.Errorf("CheckHealth() unhealthy provider = %+v, want open circuit", stats[0])
+	}
+	if stats[1].Open {
+		t.Errorf("CheckHealth() healthy provider = %+v, want closed circuit", stats[1])
+	}
+	name, _, err := src.joined.assetSource(context.Background(), "1")
+	if err != nil || name != "live" {
+		t.Errorf("assetSource() = %s, %v, want live provider", name, err)
+	}
+}
@@ -0,0 +1,96 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/steam"
+)
+
+/*
+
+objective: verify the listed item left the seller and ended up on the buyer
+
+params:
+	- seller steam id: for parsing seller's current inventory
+	- seller persona names: check for sender value of delivered assets
+	- listed assets: seller's asset snapshot taken on listing verification
+	- delivery result: sender verified result to be upgraded
+
+process:
+	- match delivered asset class and instance against listed assets
+	- download seller's json inventory
+	- check listed asset ids no longer exist on seller's inventory
+
+*/
+
+// Transfer upgrades a sender verified delivery result to transfer verified
+// when the ownership transfer of the listed assets has been confirmed.
+//
+// Result stays the same when snapshot is not available or seller's inventory
+// is private or not found since there's no way to confirm it.
+func Transfer(
+	ctx context.Context,
+	source AssetSource,
+	sellerSteamID string,
+	sellerPersonas []string,
+	listed []steam.Asset,
+	result *DeliveryResult,
+) error {
+	if result == nil || result.Status != dotagiftx.DeliveryStatusSenderVerified {
+		return nil
+	}
+	if sellerSteamID == "" || len(listed) == 0 {
+		return nil
+	}
+
+	// Delivered asset should carry the seller's gift info and the same class as the listed one.
+	delivered := slices.ContainsFunc(result.Assets, func(d steam.Asset) bool {
+		if d.GiftFrom == "" || !slices.Contains(sellerPersonas, d.GiftFrom) {
+			return false
+		}
+		return slices.ContainsFunc(listed, func(l steam.Asset) bool {
+			return l.ClassID == d.ClassID && l.InstanceID == d.InstanceID
+		})
+	})
+	if !delivered {
+		return nil
+	}
+
+	// Pull inventory data using sellerSteamID.
+	_, assets, err := source(ctx, sellerSteamID)
+	if err != nil {
+		if isAnswer(err) {
+			result.addEvidence(dotagiftx.VerifyEvidence{
+				Kind: dotagiftx.EvidenceNotTransferred,
+				Text: "seller inventory is not available to confirm transfer",
+			})
+			return nil
+		}
+		return err
+	}
+
+	// At least one of the listed assets should have left the seller's inventory.
+	for _, l := range listed {
+		held := slices.ContainsFunc(assets, func(a steam.Asset) bool {
+			return a.AssetID == l.AssetID
+		})
+		if !held {
+			result.Status = dotagiftx.DeliveryStatusTransferVerified
+			result.addEvidence(dotagiftx.VerifyEvidence{
+				Kind:   dotagiftx.EvidenceTransferred,
+				Text:   fmt.Sprintf("listed asset %s left seller inventory", l.AssetID),
+				Weight: deliveryTransferWeight,
+			})
+			return nil
+		}
+	}
+
+	result.addEvidence(dotagiftx.VerifyEvidence{
+		Kind: dotagiftx.EvidenceNotTransferred,
+		Text: "seller still holds the listed assets",
+	})
+	return nil
+}
@@ -0,0 +1,86 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/steam"
+)
+
+func TestTransfer(t *testing.T) {
+	listed := []steam.Asset{{AssetID: "100", ClassID: "c1", InstanceID: "i1", Name: "Sylvan Vedette"}}
+	delivered := []steam.Asset{{AssetID: "200", ClassID: "c1", InstanceID: "i1", Name: "Sylvan Vedette", GiftFrom: "kudarap"}}
+	sourceOf := func(assets []steam.Asset, err error) AssetSource {
+		return func(context.Context, string) (string, []steam.Asset, error) {
+			return "test", assets, err
+		}
+	}
+
+	tests := []struct {
+		name      string
+		source    AssetSource
+		listed    []steam.Asset
+		delivered []steam.Asset
+		status    dotagiftx.DeliveryStatus
+		want      dotagiftx.DeliveryStatus
+		wantErr   bool
+	}{
+		{"transferred", sourceOf(nil, nil), listed, delivered,
+			dotagiftx.DeliveryStatusSenderVerified, dotagiftx.DeliveryStatusTransferVerified, false},
+		{"seller still holds asset", sourceOf(listed, nil), listed, delivered,
+			dotagiftx.DeliveryStatusSenderVerified, dotagiftx.DeliveryStatusSenderVerified, false},
+		{"class mismatched", sourceOf(nil, nil), listed,
+			[]steam.Asset{{AssetID: "200", ClassID: "c2", InstanceID: "i1", GiftFrom: "kudarap"}},
+			dotagiftx.DeliveryStatusSenderVerified, dotagiftx.DeliveryStatusSenderVerified, false},
+		{"no snapshot", sourceOf(nil, nil), nil, delivered,
+			dotagiftx.DeliveryStatusSenderVerified, dotagiftx.DeliveryStatusSenderVerified, false},
+		{"name verified only", sourceOf(nil, nil), listed, delivered,
+			dotagiftx.DeliveryStatusNameVerified, dotagiftx.DeliveryStatusNameVerified, false},
+		{"private seller inventory", sourceOf(nil, steam.ErrInventoryPrivate), listed, delivered,
+			dotagiftx.DeliveryStatusSenderVerified, dotagiftx.DeliveryStatusSenderVerified, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &DeliveryResult{Status: tt.status, Assets: tt.delivered}
+			err := Transfer(context.Background(), tt.source, "76561198088587178", []string{"kudarap"}, tt.listed, res)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Transfer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if res.Status != tt.want {
+				t.Errorf("Transfer() status = %v, want %v", res.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestSource_MarketDelivery_transferError(t *testing.T) {
+	const sellerSteamID = "76561198088587178"
+	listed := []steam.Asset{{AssetID: "100", ClassID: "c1", InstanceID: "i1", Name: "Sylvan Vedette"}}
+	delivered := []steam.Asset{{AssetID: "200", ClassID: "c1", InstanceID: "i1", Name: "Sylvan Vedette", GiftFrom: "kudarap"}}
+	src := NewSource(func(_ context.Context, steamID string) (string, []steam.Asset, error) {
+		if steamID == sellerSteamID {
+			return "test", nil, errors.New("provider timed out")
+		}
+		return "test", delivered, nil
+	})
+
+	res, err := src.MarketDelivery(context.Background(), &dotagiftx.Market{
+		PartnerSteamID: "76561198287849998",
+		User:           &dotagiftx.User{Name: "kudarap", SteamID: sellerSteamID},
+		Item:           &dotagiftx.Item{Name: "Sylvan Vedette"},
+		Inventory:      &dotagiftx.Inventory{Snapshot: listed},
+	})
+	if err != nil {
+		t.Fatalf("MarketDelivery() error = %v, want transfer error ignored", err)
+	}
+	if res.Status != dotagiftx.DeliveryStatusSenderVerified {
+		t.Errorf("MarketDelivery() status = %v, want %v", res.Status, dotagiftx.DeliveryStatusSenderVerified)
+	}
+	last := res.Evidence[len(res.Evidence)-1]
+	if last.Kind != dotagiftx.EvidenceNotTransferred || last.Weight != 0 {
+		t.Errorf("MarketDelivery() last evidence = %+v, want inconclusive transfer", last)
+	}
+}
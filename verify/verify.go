@@ -2,33 +2,85 @@ package verify
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/logging"
 	"github.com/kudarap/dotagiftx/steam"
 )
 
+const logPrefix = "verify"
+
 // AssetSource represents inventory asset source provider.
 type AssetSource func(ctx context.Context, steamID string) (providerID string, sa []steam.Asset, err error)
 
+// Source joins asset source providers and keeps track of their health
+// across verifications.
 type Source struct {
-	providers []AssetSource
+	joined *joinedSource
+
+	snapshots       dotagiftx.InventorySnapshotService
+	snapshotHandler SnapshotHandler
+	logger          logging.Logger
 }
 
+// SnapshotHandler receives inventory events since the previous crawl of
+// steam id or the error recording its snapshot.
+type SnapshotHandler func(steamID string, events []dotagiftx.InventoryEvent, err error)
+
 func NewSource(as ...AssetSource) *Source {
-	return &Source{as}
+	return NewSourceWithConfig(Config{}, as...)
+}
+
+// NewSourceWithConfig creates a Source with circuit breaker and hedging settings.
+func NewSourceWithConfig(c Config, as ...AssetSource) *Source {
+	return &Source{joined: newJoinedSource(c, as...), logger: logging.DefaultWithPrefix(logPrefix)}
+}
+
+// SetLogger sets logger of verification errors that does not fail the result.
+func (s *Source) SetLogger(lg logging.Logger) {
+	s.logger = lg
+}
+
+// SetSnapshots records inventory snapshot of every successful crawl that
+// will be used to check assets appeared since reservation on delivery.
+func (s *Source) SetSnapshots(svc dotagiftx.InventorySnapshotService, h SnapshotHandler) {
+	s.snapshots = svc
+	s.snapshotHandler = h
+}
+
+// assetSource records the inventory snapshot of crawled assets, recording
+// errors does not fail the verification.
+func (s *Source) assetSource(ctx context.Context, steamID string) (string, []steam.Asset, error) {
+	providerID, assets, err := s.joined.assetSource(ctx, steamID)
+	if err != nil || s.snapshots == nil {
+		return providerID, assets, err
+	}
+
+	events, err := s.snapshots.Record(ctx, steamID, assets)
+	if s.snapshotHandler != nil {
+		s.snapshotHandler(steamID, events, err)
+	}
+	return providerID, assets, nil
+}
+
+// Stats returns health stats of asset source providers.
+func (s *Source) Stats() []ProviderStats {
+	return s.joined.stats()
 }
 
 type InventorResult struct {
 	Status     dotagiftx.InventoryStatus
 	Assets     []steam.Asset
 	VerifiedBy string
+	// Confidence score from 0 to 100 based on the weights of evidence found.
+	Confidence int
+	Evidence   []dotagiftx.VerifyEvidence
 }
 
-func (s *Source) Inventory(ctx context.Context, steamID, itemName string) (*InventorResult, error) {
-	src := JoinAssetSource(s.providers...)
-	res, err := Inventory(ctx, src, steamID, itemName)
+func (s *Source) Inventory(ctx context.Context, steamID string, item dotagiftx.Item) (*InventorResult, error) {
+	src := s.assetSource
+	res, err := Inventory(ctx, src, steamID, item)
 	if err != nil {
 		return nil, err
 	}
@@ -40,11 +92,20 @@ type DeliveryResult struct {
 	Status     dotagiftx.DeliveryStatus
 	Assets     []steam.Asset
 	VerifiedBy string
+	// Confidence score from 0 to 100 based on the weights of evidence found.
+	Confidence int
+	Evidence   []dotagiftx.VerifyEvidence
 }
 
-func (s *Source) Delivery(ctx context.Context, sellerPersona, steamID, itemName string) (*DeliveryResult, error) {
-	src := JoinAssetSource(s.providers...)
-	res, err := Delivery(ctx, src, sellerPersona, steamID, itemName)
+// addEvidence appends evidence and re-scores the result confidence.
+func (r *DeliveryResult) addEvidence(e dotagiftx.VerifyEvidence) {
+	r.Evidence = append(r.Evidence, e)
+	r.Confidence = dotagiftx.ConfidenceScore(r.Evidence)
+}
+
+func (s *Source) Delivery(ctx context.Context, sellerPersonas []string, steamID string, item dotagiftx.Item) (*DeliveryResult, error) {
+	src := s.assetSource
+	res, err := Delivery(ctx, src, sellerPersonas, steamID, item)
 	if err != nil {
 		return nil, err
 	}
@@ -52,18 +113,44 @@ func (s *Source) Delivery(ctx context.Context, sellerPersona, steamID, itemName
 	return res, nil
 }
 
-func JoinAssetSource(providers ...AssetSource) AssetSource {
-	return func(ctx context.Context, steamID string) (string, []steam.Asset, error) {
-		for _, source := range providers {
-			name, assets, err := source(ctx, steamID)
-			if err != nil {
-				if errors.Is(err, steam.ErrInventoryPrivate) {
-					return name, nil, err
-				}
-				continue
-			}
-			return name, assets, nil
+// MarketDelivery checks delivery of the market item including its gift date
+// against reservation and ownership transfer of the listed assets.
+func (s *Source) MarketDelivery(ctx context.Context, mkt *dotagiftx.Market) (*DeliveryResult, error) {
+	if mkt.User == nil || mkt.Item == nil {
+		return nil, fmt.Errorf("missing market data user:%#v item:%#v", mkt.User, mkt.Item)
+	}
+
+	src := s.assetSource
+	personas := mkt.SellerPersonas()
+	res, err := Delivery(ctx, src, personas, mkt.PartnerSteamID, *mkt.Item)
+	if err != nil {
+		return nil, err
+	}
+
+	GiftDate(res, personas, mkt.ReservedAt)
+	if s.snapshots != nil && mkt.ReservedAt != nil {
+		// Snapshot storage failure leaves the result without appearance evidence.
+		appeared, err := s.snapshots.AppearedSince(mkt.PartnerSteamID, *mkt.ReservedAt)
+		if err != nil {
+			s.logger.Errorf("could not check appeared assets of market %s: %s", mkt.ID, err)
 		}
-		return "", nil, fmt.Errorf("all source exhausted: %s", steamID)
+		Appeared(res, appeared)
+	}
+	if mkt.Inventory == nil {
+		return res, nil
 	}
+	if err = Transfer(ctx, src, mkt.User.SteamID, personas, mkt.Inventory.Snapshot, res); err != nil {
+		s.logger.Errorf("could not check transfer of market %s: %s", mkt.ID, err)
+		res.addEvidence(dotagiftx.VerifyEvidence{
+			Kind: dotagiftx.EvidenceNotTransferred,
+			Text: "seller inventory check failed, transfer is inconclusive",
+		})
+	}
+	return res, nil
+}
+
+// JoinAssetSource joins providers that will be tried in order, private
+// inventory answer returns early and errors move on to the next provider.
+func JoinAssetSource(providers ...AssetSource) AssetSource {
+	return newJoinedSource(Config{}, providers...).assetSource
 }
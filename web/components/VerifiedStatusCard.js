@@ -123,6 +123,10 @@ export default function VerifiedStatusCard({ market, ...other }) {
           {mapText[source.status]}.
         </Typography>
 
+        {source.evidence && source.evidence.length !== 0 && (
+          <EvidenceList confidence={source.confidence} evidence={source.evidence} />
+        )}
+
         {source.steam_assets && (
           <>
             {!isDelivery && (
@@ -244,6 +248,39 @@ export function VerifiedStatusPopover({ market, ...other }) {
 VerifiedStatusPopover.propTypes = VerifiedStatusCard.propTypes
 VerifiedStatusPopover.defaultProps = VerifiedStatusCard.defaultProps
 
+const formatWeight = weight => (weight > 0 ? `+${weight}` : `${weight}`)
+
+function EvidenceList({ confidence, evidence }) {
+  return (
+    <>
+      <Typography variant="body2" sx={{ mt: 1 }}>
+        Confidence <strong>{confidence}%</strong>
+      </Typography>
+      <Typography variant="caption" color="textSecondary" component="ul" sx={{ mt: 0, mb: 1, pl: 2 }}>
+        {evidence.map(e => (
+          <li key={e.kind + e.text}>
+            {e.weight !== 0 && <strong>{formatWeight(e.weight)}&nbsp;</strong>}
+            {e.text}
+          </li>
+        ))}
+      </Typography>
+    </>
+  )
+}
+EvidenceList.propTypes = {
+  confidence: PropTypes.number,
+  evidence: PropTypes.arrayOf(
+    PropTypes.shape({
+      kind: PropTypes.string,
+      text: PropTypes.string,
+      weight: PropTypes.number,
+    })
+  ).isRequired,
+}
+EvidenceList.defaultProps = {
+  confidence: 0,
+}
+
 function ClearedGift() {
   return (
     <Typography color="textSecondary" variant="body2" component="em">
@@ -16,6 +16,7 @@ export const MARKET_STATUS_BID_COMPLETED = 410
 export const MARKET_STATUS_REMOVED = 500
 export const MARKET_STATUS_CANCELLED = 600
 export const MARKET_STATUS_EXPIRED = 700
+export const MARKET_STATUS_UNAVAILABLE = 800
 
 export const MARKET_STATUS_MAP_TEXT = {
   [MARKET_STATUS_PENDING]: 'Pending',
@@ -26,6 +27,7 @@ export const MARKET_STATUS_MAP_TEXT = {
   [MARKET_STATUS_REMOVED]: 'Removed',
   [MARKET_STATUS_CANCELLED]: 'Cancelled',
   [MARKET_STATUS_EXPIRED]: 'Expired',
+  [MARKET_STATUS_UNAVAILABLE]: 'Unavailable',
 }
 
 export const MARKET_BID_STATUS_MAP_TEXT = {
@@ -44,6 +46,7 @@ export const MARKET_STATUS_MAP_COLOR = {
   [MARKET_STATUS_REMOVED]: 'grey',
   [MARKET_STATUS_CANCELLED]: 'orangered',
   [MARKET_STATUS_EXPIRED]: 'grey',
+  [MARKET_STATUS_UNAVAILABLE]: 'grey',
 }
 
 export const MARKET_QTY_LIMIT = 5
@@ -10,6 +10,8 @@ import Private from '@mui/icons-material/VisibilityOff'
 import Error from '@mui/icons-material/ErrorOutline'
 import ManualCheckIcon from '@mui/icons-material/CheckCircleOutline'
 import PendingIcon from '@mui/icons-material/Pending'
+import BackdatedIcon from '@mui/icons-material/History'
+import OverQuantityIcon from '@mui/icons-material/ContentCopy'
 
 const iconStyle = {
   style: {
@@ -37,22 +39,34 @@ const ultraStyle = {
   style: { ...iconStyle.style, color: 'gold' },
 }
 
+const transferStyle = {
+  style: { ...iconStyle.style, color: 'orange' },
+}
+
+const warningStyle = {
+  style: { ...iconStyle.style, color: 'tomato' },
+}
+
 export const VERIFIED_INVENTORY_PENDING = 0
 export const VERIFIED_INVENTORY_NOHIT = 100
 export const VERIFIED_INVENTORY_VERIFIED = 200
 export const VERIFIED_INVENTORY_VERIFIED_RESELL = 201
+export const VERIFIED_INVENTORY_OVER_QUANTITY = 300
 export const VERIFIED_INVENTORY_PRIVATE = 400
 export const VERIFIED_INVENTORY_ERROR = 500
 
 export const VERIFIED_INVENTORY_MAP_LABEL = {
   [VERIFIED_INVENTORY_NOHIT]: 'Not Found',
   [VERIFIED_INVENTORY_VERIFIED]: 'Item Verified',
+  [VERIFIED_INVENTORY_OVER_QUANTITY]: 'Over Quantity',
   [VERIFIED_INVENTORY_PRIVATE]: 'Private Inventory',
   [VERIFIED_INVENTORY_ERROR]: 'Error',
 }
 export const VERIFIED_INVENTORY_MAP_TEXT = {
   [VERIFIED_INVENTORY_NOHIT]: "Item not found from seller's inventory",
   [VERIFIED_INVENTORY_VERIFIED]: "Item detected from seller's inventory",
+  [VERIFIED_INVENTORY_OVER_QUANTITY]:
+    "Seller has more live listings of this item than the quantity on seller's inventory",
   [VERIFIED_INVENTORY_PRIVATE]: "Seller's inventory is private",
   [VERIFIED_INVENTORY_ERROR]: 'Error processing verification',
 }
@@ -62,27 +76,36 @@ export const VERIFIED_INVENTORY_MAP_ICON = {
   [VERIFIED_INVENTORY_NOHIT]: <NoHitIcon {...iconStyle} />,
   [VERIFIED_INVENTORY_VERIFIED]: <CheckIcon {...rareStyle} />,
   [VERIFIED_INVENTORY_VERIFIED_RESELL]: <ManualCheckIcon {...resellStyle} />,
+  [VERIFIED_INVENTORY_OVER_QUANTITY]: <OverQuantityIcon {...warningStyle} />,
   [VERIFIED_INVENTORY_PRIVATE]: <Private {...iconStyle} />,
   [VERIFIED_INVENTORY_ERROR]: <Error {...iconStyle} />,
 }
 
 const VERIFIED_DELIVERY_NOHIT = 100
 const VERIFIED_DELIVERY_NAME_VERIFIED = 200
+const VERIFIED_DELIVERY_BACKDATED = 250
 const VERIFIED_DELIVERY_SENDER_VERIFIED = 300
+const VERIFIED_DELIVERY_TRANSFER_VERIFIED = 350
 const VERIFIED_DELIVERY_PRIVATE = 400
 const VERIFIED_DELIVERY_ERROR = 500
 
 export const VERIFIED_DELIVERY_MAP_LABEL = {
   [VERIFIED_DELIVERY_NOHIT]: 'Not Found',
   [VERIFIED_DELIVERY_NAME_VERIFIED]: 'Item Verified',
+  [VERIFIED_DELIVERY_BACKDATED]: 'Gift Backdated',
   [VERIFIED_DELIVERY_SENDER_VERIFIED]: 'Sender Verified',
+  [VERIFIED_DELIVERY_TRANSFER_VERIFIED]: 'Transfer Verified',
   [VERIFIED_DELIVERY_PRIVATE]: 'Private Inventory',
   [VERIFIED_DELIVERY_ERROR]: 'Error',
 }
 export const VERIFIED_DELIVERY_MAP_TEXT = {
   [VERIFIED_DELIVERY_NOHIT]: "Item not found from buyer's inventory",
   [VERIFIED_DELIVERY_NAME_VERIFIED]: "Item verified from buyer's inventory",
+  [VERIFIED_DELIVERY_BACKDATED]:
+    'Sender avatar name matched the item, but it was gifted before the reservation',
   [VERIFIED_DELIVERY_SENDER_VERIFIED]: "Sender avatar name matched the item from buyer's inventory",
+  [VERIFIED_DELIVERY_TRANSFER_VERIFIED]:
+    "Sender verified and the listed item left the seller's inventory to the buyer's",
   [VERIFIED_DELIVERY_PRIVATE]: "Buyer's inventory is private",
   [VERIFIED_DELIVERY_ERROR]: 'Error processing verification',
 }
@@ -90,7 +113,9 @@ export const VERIFIED_DELIVERY_MAP_TEXT = {
 export const VERIFIED_DELIVERY_MAP_ICON = {
   [VERIFIED_DELIVERY_NOHIT]: <NoHitIcon {...iconStyle} />,
   [VERIFIED_DELIVERY_NAME_VERIFIED]: <CheckIcon {...rareStyle} />,
+  [VERIFIED_DELIVERY_BACKDATED]: <BackdatedIcon {...iconStyle} />,
   [VERIFIED_DELIVERY_SENDER_VERIFIED]: <DoubleCheckIcon {...ultraStyle} />,
+  [VERIFIED_DELIVERY_TRANSFER_VERIFIED]: <DoubleCheckIcon {...transferStyle} />,
   [VERIFIED_DELIVERY_PRIVATE]: <Private {...iconStyle} />,
   [VERIFIED_DELIVERY_ERROR]: <Error {...iconStyle} />,
 }
@@ -19,7 +19,7 @@ type GiftWrappedUpdate struct {
 	// job settings
 	name     string
 	interval time.Duration
-	filter   dotagiftx.Delivery
+	filters  []dotagiftx.Delivery
 }
 
 func NewGiftWrappedUpdate(
@@ -30,9 +30,9 @@ func NewGiftWrappedUpdate(
 	lg logging.Logger,
 ) *GiftWrappedUpdate {
 	falsePtr := false
-	f := dotagiftx.Delivery{
-		GiftOpened: &falsePtr,
-		Status:     dotagiftx.DeliveryStatusSenderVerified,
+	f := []dotagiftx.Delivery{
+		{GiftOpened: &falsePtr, Status: dotagiftx.DeliveryStatusSenderVerified},
+		{GiftOpened: &falsePtr, Status: dotagiftx.DeliveryStatusTransferVerified},
 	}
 	return &GiftWrappedUpdate{
 		ds, dg, ms, vs, lg,
@@ -49,7 +49,16 @@ func (gw *GiftWrappedUpdate) Run(ctx context.Context) error {
 		gw.logger.Println("GIFT WRAPPED UPDATE BENCHMARK TIME", time.Since(bs))
 	}()
 
-	opts := dotagiftx.FindOpts{Filter: gw.filter}
+	for _, f := range gw.filters {
+		if err := gw.update(ctx, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gw *GiftWrappedUpdate) update(ctx context.Context, filter dotagiftx.Delivery) error {
+	opts := dotagiftx.FindOpts{Filter: filter}
 	opts.Sort = "updated_at:desc"
 	opts.Limit = 10
 	opts.Page = 0
@@ -62,6 +71,9 @@ func (gw *GiftWrappedUpdate) Run(ctx context.Context) error {
 		}
 
 		for _, dd := range deliveries {
+			if err = ctx.Err(); err != nil {
+				return err
+			}
 			start := time.Now()
 
 			gw.logger.Infoln("processing gift wrapped update", dd.ID, *dd.GiftOpened, dd.Retries)
@@ -80,7 +92,7 @@ func (gw *GiftWrappedUpdate) Run(ctx context.Context) error {
 				continue
 			}
 
-			result, err := gw.source.Delivery(ctx, mkt.User.Name, mkt.PartnerSteamID, mkt.Item.Name)
+			result, err := gw.source.MarketDelivery(ctx, mkt)
 			if err != nil {
 				gw.logger.Errorf("delivery verification error: %s", err)
 				continue
@@ -92,6 +104,8 @@ func (gw *GiftWrappedUpdate) Run(ctx context.Context) error {
 				Status:     result.Status,
 				Assets:     result.Assets,
 				VerifiedBy: result.VerifiedBy,
+				Confidence: result.Confidence,
+				Evidence:   result.Evidence,
 				ElapsedMs:  time.Since(start).Milliseconds(),
 			})
 			if err != nil {
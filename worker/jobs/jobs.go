@@ -1,5 +1,25 @@
 package jobs
 
+import (
+	"context"
+	"time"
+)
+
 type cacheRemover interface {
 	BulkDel(keyPrefix string) error
 }
+
+type cacheSetter interface {
+	Set(key string, val interface{}, expr time.Duration) error
+}
+
+// rest pauses the job between batch process and returns early with
+// an error when the context is done.
+func rest(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
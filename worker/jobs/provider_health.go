@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/kudarap/dotagiftx/logging"
+	"github.com/kudarap/dotagiftx/verify"
+)
+
+// ProviderHealth represents a job that checks and reports asset source
+// providers health for operators.
+type ProviderHealth struct {
+	source providerStatsGetter
+	cache  cacheSetter
+
+	name     string
+	interval time.Duration
+	logger   logging.Logger
+}
+
+func NewProviderHealth(source providerStatsGetter, cache cacheSetter, lg logging.Logger) *ProviderHealth {
+	return &ProviderHealth{
+		source:   source,
+		cache:    cache,
+		name:     "provider_health",
+		interval: time.Minute * 10,
+		logger:   lg,
+	}
+}
+
+func (ph *ProviderHealth) String() string { return ph.name }
+
+func (ph *ProviderHealth) Interval() time.Duration { return ph.interval }
+
+func (ph *ProviderHealth) Run(ctx context.Context) error {
+	ph.source.CheckHealth(ctx)
+	stats := ph.source.Stats()
+	for _, s := range stats {
+		ph.logger.Printf(
+			"provider:%s weight:%d requests:%d errors:%d error_rate:%.2f latency:%s private_agreed:%d/%d open:%t health_error:%q",
+			s.Name, s.Weight, s.Requests, s.Errors, s.ErrorRate, s.Latency, s.PrivateAgreed, s.PrivateChecks, s.Open, s.HealthError,
+		)
+	}
+
+	// Stats expires when the worker stops reporting.
+	if err := ph.cache.Set(verify.StatsCacheKey, stats, ph.interval*2); err != nil {
+		ph.logger.Errorf("could not publish provider stats: %s", err)
+	}
+	return nil
+}
+
+type providerStatsGetter interface {
+	CheckHealth(ctx context.Context)
+	Stats() []verify.ProviderStats
+}
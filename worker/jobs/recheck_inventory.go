@@ -19,7 +19,7 @@ type RecheckInventory struct {
 	// job settings
 	name     string
 	interval time.Duration
-	filter   dotagiftx.Inventory
+	filters  []dotagiftx.Inventory
 }
 
 func NewRecheckInventory(
@@ -28,7 +28,10 @@ func NewRecheckInventory(
 	as *verify.Source,
 	lg logging.Logger,
 ) *RecheckInventory {
-	f := dotagiftx.Inventory{Status: dotagiftx.InventoryStatusNoHit}
+	f := []dotagiftx.Inventory{
+		{Status: dotagiftx.InventoryStatusNoHit},
+		{Status: dotagiftx.InventoryStatusOverQuantity},
+	}
 	return &RecheckInventory{
 		is, ms, as, lg,
 		"recheck_inventory", time.Hour, f}
@@ -44,7 +47,16 @@ func (ri *RecheckInventory) Run(ctx context.Context) error {
 		ri.logger.Println("RECHECK INVENTORY BENCHMARK TIME", time.Since(bs))
 	}()
 
-	opts := dotagiftx.FindOpts{Filter: ri.filter}
+	for _, f := range ri.filters {
+		if err := ri.recheck(ctx, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ri *RecheckInventory) recheck(ctx context.Context, filter dotagiftx.Inventory) error {
+	opts := dotagiftx.FindOpts{Filter: filter}
 	opts.Sort = "updated_at:desc"
 	// opts.Limit = 10
 	opts.Page = 0
@@ -72,7 +84,7 @@ func (ri *RecheckInventory) Run(ctx context.Context) error {
 			continue
 		}
 
-		result, err := ri.source.Inventory(ctx, mkt.User.SteamID, mkt.Item.Name)
+		result, err := ri.source.Inventory(ctx, mkt.User.SteamID, *mkt.Item)
 		if err != nil {
 			ri.logger.Errorf("skipped process! source error user:%#v item:%#v err:%#v", mkt.User, mkt.Item, err)
 			continue
@@ -84,13 +96,17 @@ func (ri *RecheckInventory) Run(ctx context.Context) error {
 			Status:     result.Status,
 			Assets:     result.Assets,
 			VerifiedBy: result.VerifiedBy,
+			Confidence: result.Confidence,
+			Evidence:   result.Evidence,
 			ElapsedMs:  time.Since(start).Milliseconds(),
 		})
 		if err != nil {
 			ri.logger.Errorln(mkt.User.SteamID, mkt.Item.Name, result.Status, err)
 		}
 
-		time.Sleep(time.Second / 4)
+		if err = rest(ctx, time.Second/4); err != nil {
+			return err
+		}
 	}
 
 	return nil
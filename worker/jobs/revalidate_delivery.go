@@ -56,6 +56,9 @@ func (rd *RevalidateDelivery) Run(ctx context.Context) error {
 		}
 
 		for _, mkt := range res {
+			if err = ctx.Err(); err != nil {
+				return err
+			}
 			start := time.Now()
 
 			if mkt.User == nil || mkt.Item == nil {
@@ -72,7 +75,7 @@ func (rd *RevalidateDelivery) Run(ctx context.Context) error {
 				continue
 			}
 
-			result, err := rd.source.Delivery(ctx, mkt.User.Name, mkt.PartnerSteamID, mkt.Item.Name)
+			result, err := rd.source.MarketDelivery(ctx, &mkt)
 			if err != nil {
 				continue
 			}
@@ -83,6 +86,8 @@ func (rd *RevalidateDelivery) Run(ctx context.Context) error {
 				Status:     result.Status,
 				Assets:     result.Assets,
 				VerifiedBy: result.VerifiedBy,
+				Confidence: result.Confidence,
+				Evidence:   result.Evidence,
 				ElapsedMs:  time.Since(start).Milliseconds(),
 			})
 			if err != nil {
@@ -8,8 +8,7 @@ import (
 )
 
 type CleanPhantasmCache struct {
-	service     phantasmCacheCleaner
-	cacheMaxAge time.Duration
+	service phantasmCacheCleaner
 
 	name     string
 	interval time.Duration
@@ -18,11 +17,10 @@ type CleanPhantasmCache struct {
 
 func NewSweepPhantasmCache(cleaner phantasmCacheCleaner, lg logging.Logger) *CleanPhantasmCache {
 	return &CleanPhantasmCache{
-		service:     cleaner,
-		cacheMaxAge: time.Hour * 24 * 30, // 30 days
-		name:        "clean_phantasm_cache",
-		interval:    time.Hour * 24,
-		logger:      lg,
+		service:  cleaner,
+		name:     "clean_phantasm_cache",
+		interval: time.Hour * 24,
+		logger:   lg,
 	}
 }
 
@@ -31,8 +29,8 @@ func (c *CleanPhantasmCache) String() string { return c.name }
 func (c *CleanPhantasmCache) Interval() time.Duration { return c.interval }
 
 func (c *CleanPhantasmCache) Run(ctx context.Context) error {
-	c.logger.Println("cleaning phantasm cache older than", c.cacheMaxAge)
-	if err := c.service.CleanLocalCache(ctx, c.cacheMaxAge); err != nil {
+	c.logger.Println("cleaning expired phantasm cache")
+	if err := c.service.CleanLocalCache(ctx); err != nil {
 		return err
 	}
 	c.logger.Println("phantasm cache cleaned!")
@@ -40,5 +38,5 @@ func (c *CleanPhantasmCache) Run(ctx context.Context) error {
 }
 
 type phantasmCacheCleaner interface {
-	CleanLocalCache(ctx context.Context, maxAge time.Duration) error
+	CleanLocalCache(ctx context.Context) error
 }
@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/logging"
+)
+
+type steamPlayerGetter interface {
+	Player(steamID string) (*dotagiftx.SteamPlayer, error)
+}
+
+// SyncSteamProfile represents a job that re-syncs active sellers steam profile
+// to keep track of their persona name history.
+type SyncSteamProfile struct {
+	userSvc   dotagiftx.UserService
+	marketStg dotagiftx.MarketStorage
+	steam     steamPlayerGetter
+	cache     cacheRemover
+	logger    logging.Logger
+	// job settings
+	name     string
+	interval time.Duration
+	filter   dotagiftx.Market
+}
+
+func NewSyncSteamProfile(
+	us dotagiftx.UserService,
+	ms dotagiftx.MarketStorage,
+	sp steamPlayerGetter,
+	cc cacheRemover,
+	lg logging.Logger,
+) *SyncSteamProfile {
+	f := dotagiftx.Market{Type: dotagiftx.MarketTypeAsk, Status: dotagiftx.MarketStatusLive}
+	return &SyncSteamProfile{
+		us, ms, sp, cc, lg,
+		"sync_steam_profile", time.Hour * 24, f}
+}
+
+func (sp *SyncSteamProfile) String() string { return sp.name }
+
+func (sp *SyncSteamProfile) Interval() time.Duration { return sp.interval }
+
+// Run syncs steam profile of users that has live listings.
+func (sp *SyncSteamProfile) Run(ctx context.Context) error {
+	bs := time.Now()
+	defer func() {
+		sp.logger.Println("SYNC STEAM PROFILE BENCHMARK TIME", time.Since(bs))
+	}()
+
+	opts := dotagiftx.FindOpts{Filter: sp.filter}
+	opts.Sort = "updated_at"
+	opts.Desc = true
+	opts.Limit = 100
+	opts.Page = 0
+
+	synced := map[string]struct{}{}
+	for {
+		res, err := sp.marketStg.Find(opts)
+		if err != nil {
+			return err
+		}
+
+		for _, mkt := range res {
+			if mkt.User == nil || mkt.User.SteamID == "" {
+				continue
+			}
+			if _, ok := synced[mkt.User.SteamID]; ok {
+				continue
+			}
+			synced[mkt.User.SteamID] = struct{}{}
+
+			if err = sp.sync(mkt.User.SteamID); err != nil {
+				sp.logger.Errorln(mkt.User.SteamID, err)
+			}
+
+			if err = rest(ctx, time.Second/4); err != nil {
+				return err
+			}
+		}
+
+		// Is there more?
+		if len(res) < opts.Limit {
+			return nil
+		}
+		opts.Page++
+	}
+}
+
+func (sp *SyncSteamProfile) sync(steamID string) error {
+	player, err := sp.steam.Player(steamID)
+	if err != nil {
+		return fmt.Errorf("could not get steam player: %s", err)
+	}
+	// Profile image is left to login sync since worker files are not served.
+	u, err := sp.userSvc.PersonaSync(player)
+	if err != nil {
+		return fmt.Errorf("could not sync steam profile: %s", err)
+	}
+	sp.logger.Println("synced", u.SteamID, u.Name, len(u.Personas))
+
+	if err = sp.cache.BulkDel(fmt.Sprintf("users/%s*", u.SteamID)); err != nil {
+		sp.logger.Errorf("invalidate user cache: %s", err)
+	}
+	return nil
+}
@@ -61,7 +61,8 @@ func (vd *VerifyDelivery) Run(ctx context.Context) error {
 
 			// Skip verified statuses.
 			if mkt.DeliveryStatus == dotagiftx.DeliveryStatusNameVerified ||
-				mkt.DeliveryStatus == dotagiftx.DeliveryStatusSenderVerified {
+				mkt.DeliveryStatus == dotagiftx.DeliveryStatusSenderVerified ||
+				mkt.DeliveryStatus == dotagiftx.DeliveryStatusTransferVerified {
 				continue
 			}
 
@@ -70,7 +71,7 @@ func (vd *VerifyDelivery) Run(ctx context.Context) error {
 				continue
 			}
 
-			result, err := vd.source.Delivery(ctx, mkt.User.Name, mkt.PartnerSteamID, mkt.Item.Name)
+			result, err := vd.source.MarketDelivery(ctx, &mkt)
 			if err != nil {
 				continue
 			}
@@ -81,13 +82,17 @@ func (vd *VerifyDelivery) Run(ctx context.Context) error {
 				Status:     result.Status,
 				Assets:     result.Assets,
 				VerifiedBy: result.VerifiedBy,
+				Confidence: result.Confidence,
+				Evidence:   result.Evidence,
 				ElapsedMs:  time.Since(start).Milliseconds(),
 			})
 			if err != nil {
 				vd.logger.Errorln(mkt.User.SteamID, mkt.Item.Name, result.Status, err)
 			}
 
-			time.Sleep(time.Second / 4)
+			if err = rest(ctx, time.Second/4); err != nil {
+				return err
+			}
 		}
 
 		// Is there more?
@@ -61,6 +61,7 @@ func (vi *VerifyInventory) Run(ctx context.Context) error {
 
 			// Skip verified statuses.
 			if mkt.InventoryStatus == dotagiftx.InventoryStatusVerified ||
+				mkt.InventoryStatus == dotagiftx.InventoryStatusOverQuantity ||
 				mkt.InventoryStatus == dotagiftx.InventoryStatusNoHit {
 
 				// TODO! might remove items
@@ -72,7 +73,7 @@ func (vi *VerifyInventory) Run(ctx context.Context) error {
 				continue
 			}
 
-			result, err := vi.source.Inventory(ctx, mkt.User.SteamID, mkt.Item.Name)
+			result, err := vi.source.Inventory(ctx, mkt.User.SteamID, *mkt.Item)
 			if err != nil {
 				continue
 			}
@@ -83,13 +84,17 @@ func (vi *VerifyInventory) Run(ctx context.Context) error {
 				Status:     result.Status,
 				Assets:     result.Assets,
 				VerifiedBy: result.VerifiedBy,
+				Confidence: result.Confidence,
+				Evidence:   result.Evidence,
 				ElapsedMs:  time.Since(start).Milliseconds(),
 			})
 			if err != nil {
 				vi.logger.Errorln(mkt.User.SteamID, mkt.Item.Name, result.Status, err)
 			}
 
-			time.Sleep(time.Second / 4)
+			if err = rest(ctx, time.Second/4); err != nil {
+				return err
+			}
 		}
 
 		// Is there more?
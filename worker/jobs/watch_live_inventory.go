@@ -0,0 +1,225 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/logging"
+	"github.com/kudarap/dotagiftx/verify"
+)
+
+// watchMissPrefix is the cache key prefix of listings missed by the previous run.
+const watchMissPrefix = "watch_live_inventory_miss:"
+
+type watchCache interface {
+	Get(key string) (val string, err error)
+	Set(key string, val interface{}, expr time.Duration) error
+	Del(key string) error
+	BulkDel(keyPrefix string) error
+}
+
+type rankScoreUpdater interface {
+	UpdateUserRankScore(userID string) error
+}
+
+// WatchLiveInventory represents a job that re-verifies seller inventories of
+// live asks and marks listings as unavailable when the item was traded away
+// elsewhere, so the catalog lowest ask only counts items that still exist.
+// Sellers gets alerted when their listing becomes unavailable.
+type WatchLiveInventory struct {
+	inventorySvc dotagiftx.InventoryService
+	marketStg    dotagiftx.MarketStorage
+	catalogStg   dotagiftx.CatalogStorage
+	rankScore    rankScoreUpdater
+	alertSvc     dotagiftx.SellerAlertService
+	source       *verify.Source
+	cache        watchCache
+	logger       logging.Logger
+	// job settings
+	name     string
+	interval time.Duration
+	filter   dotagiftx.Market
+	batch    int
+	pause    time.Duration
+}
+
+func NewWatchLiveInventory(
+	is dotagiftx.InventoryService,
+	ms dotagiftx.MarketStorage,
+	cs dotagiftx.CatalogStorage,
+	rs rankScoreUpdater,
+	as dotagiftx.SellerAlertService,
+	vs *verify.Source,
+	cc watchCache,
+	lg logging.Logger,
+) *WatchLiveInventory {
+	f := dotagiftx.Market{Type: dotagiftx.MarketTypeAsk, Status: dotagiftx.MarketStatusLive}
+	return &WatchLiveInventory{
+		is, ms, cs, rs, as, vs, cc, lg,
+		"watch_live_inventory", time.Hour * 6, f, 100, time.Second / 4}
+}
+
+func (wi *WatchLiveInventory) String() string { return wi.name }
+
+func (wi *WatchLiveInventory) Interval() time.Duration { return wi.interval }
+
+func (wi *WatchLiveInventory) Run(ctx context.Context) error {
+	bs := time.Now()
+	defer func() {
+		wi.logger.Println("WATCH LIVE INVENTORY BENCHMARK TIME", time.Since(bs))
+	}()
+
+	// Watching changes market status and updated_at, collecting the ids first
+	// keeps the pages from shifting.
+	ids, err := wi.liveIDs()
+	if err != nil {
+		return err
+	}
+
+	var unavailable int
+	for _, id := range ids {
+		mkt, err := wi.market(id)
+		if err != nil {
+			wi.logger.Errorln(id, err)
+			continue
+		}
+		// Market might have been reserved or removed since collected.
+		if mkt == nil || mkt.Status != dotagiftx.MarketStatusLive {
+			continue
+		}
+
+		ok, err := wi.watch(ctx, *mkt)
+		if err != nil {
+			wi.logger.Errorln(mkt.ID, err)
+		}
+		if ok {
+			unavailable++
+		}
+
+		if err = rest(ctx, wi.pause); err != nil {
+			return err
+		}
+	}
+
+	if unavailable == 0 {
+		return nil
+	}
+	// svc_market market is the prefixed used for caching market related data.
+	if err := wi.cache.BulkDel("svc_market"); err != nil {
+		wi.logger.Errorf("could not perform bulk delete on market cache: %s", err)
+		return err
+	}
+	return nil
+}
+
+func (wi *WatchLiveInventory) liveIDs() ([]string, error) {
+	opts := dotagiftx.FindOpts{Filter: wi.filter}
+	opts.IndexKey = "status"
+	opts.Sort = "created_at"
+	opts.Limit = wi.batch
+	opts.Page = 0
+
+	var ids []string
+	for {
+		res, err := wi.marketStg.Find(opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, mkt := range res {
+			ids = append(ids, mkt.ID)
+		}
+
+		// Is there more?
+		if len(res) < opts.Limit {
+			break
+		}
+		opts.Page++
+	}
+	return ids, nil
+}
+
+func (wi *WatchLiveInventory) market(id string) (*dotagiftx.Market, error) {
+	f := dotagiftx.FindOpts{Filter: dotagiftx.Market{ID: id}}
+	markets, err := wi.marketStg.Find(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(markets) == 0 {
+		return nil, nil
+	}
+	mkt := markets[0]
+	return &mkt, nil
+}
+
+// watch re-verifies the seller inventory of a live ask, the listing becomes
+// unavailable when this job missed the item on two consecutive runs since
+// providers might miss items on a single crawl.
+func (wi *WatchLiveInventory) watch(ctx context.Context, mkt dotagiftx.Market) (unavailable bool, err error) {
+	// Only verified asks are watched and resells do not verify items.
+	if mkt.IsResell() ||
+		(mkt.InventoryStatus != dotagiftx.InventoryStatusVerified &&
+			mkt.InventoryStatus != dotagiftx.InventoryStatusNoHit) {
+		return false, nil
+	}
+	if mkt.User == nil || mkt.Item == nil {
+		return false, fmt.Errorf("missing data user:%#v item:%#v", mkt.User, mkt.Item)
+	}
+
+	start := time.Now()
+	result, err := wi.source.Inventory(ctx, mkt.User.SteamID, *mkt.Item)
+	if err != nil {
+		return false, err
+	}
+
+	wi.logger.Println(mkt.User.SteamID, mkt.Item.Name, result.Status)
+	err = wi.inventorySvc.Set(ctx, &dotagiftx.Inventory{
+		MarketID:   mkt.ID,
+		Status:     result.Status,
+		Assets:     result.Assets,
+		VerifiedBy: result.VerifiedBy,
+		Confidence: result.Confidence,
+		Evidence:   result.Evidence,
+		ElapsedMs:  time.Since(start).Milliseconds(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	missKey := watchMissPrefix + mkt.ID
+	if result.Status != dotagiftx.InventoryStatusNoHit {
+		if err = wi.cache.Del(missKey); err != nil {
+			wi.logger.Errorf("could not clear missed listing %s: %s", mkt.ID, err)
+		}
+		return false, nil
+	}
+	missed, err := wi.cache.Get(missKey)
+	if err != nil {
+		return false, err
+	}
+	if missed == "" {
+		// Miss expires when the next run did not confirm it.
+		return false, wi.cache.Set(missKey, start, wi.interval*2)
+	}
+
+	if err = wi.marketStg.Update(&dotagiftx.Market{
+		ID:     mkt.ID,
+		Status: dotagiftx.MarketStatusUnavailable,
+	}); err != nil {
+		return false, err
+	}
+	if err = wi.cache.Del(missKey); err != nil {
+		wi.logger.Errorf("could not clear missed listing %s: %s", mkt.ID, err)
+	}
+	if err = wi.rankScore.UpdateUserRankScore(mkt.UserID); err != nil {
+		wi.logger.Errorf("could not update user rank score %s: %s", mkt.UserID, err)
+	}
+	if _, err = wi.catalogStg.Index(mkt.ItemID); err != nil {
+		wi.logger.Errorf("could not index catalog %s: %s", mkt.ItemID, err)
+	}
+	if err = wi.alertSvc.ListingUnavailable(mkt); err != nil {
+		wi.logger.Errorf("could not alert seller of %s: %s", mkt.ID, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,218 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kudarap/dotagiftx"
+	"github.com/kudarap/dotagiftx/logging"
+	"github.com/kudarap/dotagiftx/steam"
+	"github.com/kudarap/dotagiftx/verify"
+)
+
+type fakeWatchMarketStorage struct {
+	dotagiftx.MarketStorage
+	markets []dotagiftx.Market
+}
+
+// Find pages live markets by their current status like the data store does.
+func (s *fakeWatchMarketStorage) Find(o dotagiftx.FindOpts) ([]dotagiftx.Market, error) {
+	f := o.Filter.(dotagiftx.Market)
+	var res []dotagiftx.Market
+	for _, m := range s.markets {
+		if (f.ID != "" && m.ID == f.ID) || (f.ID == "" && m.Status == f.Status) {
+			res = append(res, m)
+		}
+	}
+	if o.Limit == 0 {
+		return res, nil
+	}
+	start := min(o.Page*o.Limit, len(res))
+	return res[start:min(start+o.Limit, len(res))], nil
+}
+
+func (s *fakeWatchMarketStorage) Update(in *dotagiftx.Market) error {
+	for i := range s.markets {
+		if s.markets[i].ID == in.ID {
+			s.markets[i].Status = in.Status
+		}
+	}
+	return nil
+}
+
+func (s *fakeWatchMarketStorage) status(id string) dotagiftx.MarketStatus {
+	for _, m := range s.markets {
+		if m.ID == id {
+			return m.Status
+		}
+	}
+	return 0
+}
+
+type fakeInventoryService struct {
+	dotagiftx.InventoryService
+	markets *fakeWatchMarketStorage
+}
+
+func (s *fakeInventoryService) Set(_ context.Context, inv *dotagiftx.Inventory) error {
+	for i := range s.markets.markets {
+		if s.markets.markets[i].ID == inv.MarketID {
+			s.markets.markets[i].InventoryStatus = inv.Status
+		}
+	}
+	return nil
+}
+
+type fakeCatalogStorage struct {
+	dotagiftx.CatalogStorage
+	indexed []string
+}
+
+func (s *fakeCatalogStorage) Index(itemID string) (*dotagiftx.Catalog, error) {
+	s.indexed = append(s.indexed, itemID)
+	return nil, nil
+}
+
+type fakeRankScoreUpdater []string
+
+func (u *fakeRankScoreUpdater) UpdateUserRankScore(userID string) error {
+	*u = append(*u, userID)
+	return nil
+}
+
+type fakeSellerAlertService struct {
+	dotagiftx.SellerAlertService
+	unavailable []string
+}
+
+func (s *fakeSellerAlertService) ListingUnavailable(mkt dotagiftx.Market) error {
+	s.unavailable = append(s.unavailable, mkt.ID)
+	return nil
+}
+
+type fakeWatchCache map[string]string
+
+func (c fakeWatchCache) Get(key string) (string, error) { return c[key], nil }
+
+func (c fakeWatchCache) Set(key string, val interface{}, _ time.Duration) error {
+	c[key] = fmt.Sprint(val)
+	return nil
+}
+
+func (c fakeWatchCache) Del(key string) error {
+	delete(c, key)
+	return nil
+}
+
+func (c fakeWatchCache) BulkDel(string) error { return nil }
+
+func newTestWatchLiveInventory(markets []dotagiftx.Market, owned map[string][]steam.Asset) (*WatchLiveInventory, *fakeWatchMarketStorage, fakeWatchCache) {
+	marketStg := &fakeWatchMarketStorage{markets: markets}
+	source := verify.NewSource(func(_ context.Context, steamID string) (string, []steam.Asset, error) {
+		return "test", owned[steamID], nil
+	})
+	cache := fakeWatchCache{}
+	wi := NewWatchLiveInventory(
+		&fakeInventoryService{markets: marketStg},
+		marketStg,
+		&fakeCatalogStorage{},
+		&fakeRankScoreUpdater{},
+		&fakeSellerAlertService{},
+		source,
+		cache,
+		logging.Default(),
+	)
+	wi.batch = 2
+	wi.pause = 0
+	return wi, marketStg, cache
+}
+
+func testLiveAsk(id, steamID string) dotagiftx.Market {
+	return dotagiftx.Market{
+		ID:              id,
+		ItemID:          "sylvan",
+		Type:            dotagiftx.MarketTypeAsk,
+		Status:          dotagiftx.MarketStatusLive,
+		InventoryStatus: dotagiftx.InventoryStatusVerified,
+		UserID:          "u-" + steamID,
+		User:            &dotagiftx.User{SteamID: steamID},
+		Item:            &dotagiftx.Item{Name: "Sylvan Vedette"},
+	}
+}
+
+func TestWatchLiveInventory_consecutiveMisses(t *testing.T) {
+	owned := map[string][]steam.Asset{
+		"holder": {{AssetID: "1", Name: "Sylvan Vedette", GiftOnce: true}},
+	}
+	wi, marketStg, cache := newTestWatchLiveInventory([]dotagiftx.Market{
+		testLiveAsk("kept", "holder"),
+		testLiveAsk("traded", "trader"),
+	}, owned)
+	ctx := context.Background()
+
+	// First miss only flags the listing.
+	if err := wi.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := marketStg.status("traded"); got != dotagiftx.MarketStatusLive {
+		t.Fatalf("Run() first miss status = %v, want live", got)
+	}
+	if _, ok := cache[watchMissPrefix+"traded"]; !ok {
+		t.Fatalf("Run() first miss should be recorded")
+	}
+
+	// Item came back in between clears the miss.
+	owned["trader"] = owned["holder"]
+	if err := wi.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache[watchMissPrefix+"traded"]; ok {
+		t.Fatalf("Run() hit should clear recorded miss")
+	}
+
+	// Two consecutive misses make the listing unavailable.
+	delete(owned, "trader")
+	for range 2 {
+		if err := wi.Run(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := marketStg.status("traded"); got != dotagiftx.MarketStatusUnavailable {
+		t.Errorf("Run() consecutive misses status = %v, want unavailable", got)
+	}
+	if got := marketStg.status("kept"); got != dotagiftx.MarketStatusLive {
+		t.Errorf("Run() owned item status = %v, want live", got)
+	}
+	if len(wi.catalogStg.(*fakeCatalogStorage).indexed) != 1 {
+		t.Errorf("Run() catalog indexed %v, want once on unavailable", wi.catalogStg.(*fakeCatalogStorage).indexed)
+	}
+	if got := *wi.rankScore.(*fakeRankScoreUpdater); len(got) != 1 || got[0] != "u-trader" {
+		t.Errorf("Run() rank score updated %v, want seller of unavailable listing", got)
+	}
+	if got := wi.alertSvc.(*fakeSellerAlertService).unavailable; len(got) != 1 || got[0] != "traded" {
+		t.Errorf("Run() sellers alerted %v, want unavailable listing", got)
+	}
+}
+
+func TestWatchLiveInventory_pagination(t *testing.T) {
+	var markets []dotagiftx.Market
+	for i := range 5 {
+		markets = append(markets, testLiveAsk(fmt.Sprint(i), "trader"))
+	}
+	wi, marketStg, cache := newTestWatchLiveInventory(markets, nil)
+	// Every listing was missed on the previous run.
+	for _, m := range markets {
+		cache[watchMissPrefix+m.ID] = "missed"
+	}
+
+	if err := wi.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range markets {
+		if got := marketStg.status(m.ID); got != dotagiftx.MarketStatusUnavailable {
+			t.Errorf("Run() market %s status = %v, want unavailable", m.ID, got)
+		}
+	}
+}
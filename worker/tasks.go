@@ -3,6 +3,7 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -12,10 +13,23 @@ import (
 	"github.com/kudarap/dotagiftx/verify"
 )
 
+// taskRequeueTimeout is the max duration for returning cancelled tasks back to the queue.
+const taskRequeueTimeout = time.Second * 5
+
+var errTaskPayloadVersion = errors.New("unsupported task payload version")
+
 type TaskProcessor struct {
-	queue taskQueue
-	rate  time.Duration
+	queue    taskQueue
+	rate     time.Duration
+	quit     chan struct{}
+	handlers map[dotagiftx.TaskType]taskHandler
+
+	// mu guards stopped so no task is added on the wait group after Stop.
+	mu      sync.Mutex
+	stopped bool
 
+	marketStg            dotagiftx.MarketStorage
+	itemStg              dotagiftx.ItemStorage
 	inventorySvc         dotagiftx.InventoryService
 	deliverySvc          dotagiftx.DeliveryService
 	verify               *verify.Source
@@ -25,25 +39,45 @@ type TaskProcessor struct {
 func NewTaskProcessor(
 	rate time.Duration,
 	queue taskQueue,
+	marketStg dotagiftx.MarketStorage,
+	itemStg dotagiftx.ItemStorage,
 	inventorySvc dotagiftx.InventoryService,
 	deliverySvc dotagiftx.DeliveryService,
 	source *verify.Source,
 	invInvalidator inventoryInvalidator,
 ) *TaskProcessor {
-	return &TaskProcessor{
+	p := &TaskProcessor{
 		queue:                queue,
 		rate:                 rate,
+		quit:                 make(chan struct{}),
+		marketStg:            marketStg,
+		itemStg:              itemStg,
 		inventorySvc:         inventorySvc,
 		deliverySvc:          deliverySvc,
 		verify:               source,
 		inventoryInvalidator: invInvalidator,
 	}
+	p.handlers = map[dotagiftx.TaskType]taskHandler{
+		dotagiftx.TaskTypeVerifyInventory: p.taskVerifyInventory,
+		dotagiftx.TaskTypeVerifyDelivery:  p.taskVerifyDelivery,
+	}
+	return p
 }
 
-func (p *TaskProcessor) Run(wg *sync.WaitGroup) {
-	ctx := context.Background()
+type taskHandler func(ctx context.Context, payload interface{}) error
+
+// Run polls and process tasks from the queue until Stop is called.
+//
+// Running task receives ctx and will be returned to pending status when
+// it gets cancelled before it finishes.
+func (p *TaskProcessor) Run(ctx context.Context, wg *sync.WaitGroup) {
 	for {
-		time.Sleep(p.rate)
+		select {
+		case <-p.quit:
+			log.Println("task processor stopped")
+			return
+		case <-time.After(p.rate):
+		}
 
 		start := time.Now()
 
@@ -57,7 +91,10 @@ func (p *TaskProcessor) Run(wg *sync.WaitGroup) {
 		}
 
 		task := *t
-		wg.Add(1)
+		if !p.begin(wg) {
+			log.Println("task processor stopped")
+			return
+		}
 
 		task.Status = dotagiftx.TaskStatusProcessing
 		if err = p.queue.Update(ctx, task); err != nil {
@@ -67,22 +104,32 @@ func (p *TaskProcessor) Run(wg *sync.WaitGroup) {
 		}
 		log.Println("task get", task.ID, task.Type, task.Priority)
 
-		var run func(context.Context, interface{}) error
-		switch task.Type {
-		case dotagiftx.TaskTypeVerifyInventory:
-			run = p.taskVerifyInventory
-		case dotagiftx.TaskTypeVerifyDelivery:
-			run = p.taskVerifyDelivery
+		run, ok := p.handlers[task.Type]
+		if !ok {
+			run = func(context.Context, interface{}) error {
+				return fmt.Errorf("unsupported task type: %d", task.Type)
+			}
 		}
 
 		log.Println("task processing...", task.ID, task.Type)
 		err = run(ctx, task.Payload)
 		task.ElapsedMs = time.Since(start).Milliseconds()
+		// Finished task status is saved even when ctx got cancelled while
+		// draining, otherwise it stays on processing.
+		updateCtx := context.WithoutCancel(ctx)
+		if err != nil && ctx.Err() != nil {
+			// Task could not finish before the processor got cancelled,
+			// return it to the queue so that it can be picked up again.
+			log.Printf("WRN! task cancelled: %s %s", task.Type, err)
+			p.requeue(task, err)
+			wg.Done()
+			continue
+		}
 		if err != nil {
 			log.Printf("ERR! running tasks: %s %s", task.Type, err)
 			task.Status = dotagiftx.TaskStatusError
 			task.Note = fmt.Sprintf("err: %s", err)
-			if err = p.queue.Update(ctx, task); err != nil {
+			if err = p.queue.Update(updateCtx, task); err != nil {
 				log.Printf("ERR! could not run task: %s", err)
 			}
 			wg.Done()
@@ -91,20 +138,58 @@ func (p *TaskProcessor) Run(wg *sync.WaitGroup) {
 
 		task.Status = dotagiftx.TaskStatusDone
 		log.Println("task done!", task.ID, time.Duration(task.ElapsedMs)*time.Millisecond)
-		if err = p.queue.Update(ctx, task); err != nil {
+		if err = p.queue.Update(updateCtx, task); err != nil {
 			log.Printf("ERR! could not update task: %s", err)
 		}
 		wg.Done()
 	}
 }
 
+// Stop stops the processor from getting new tasks from the queue.
+func (p *TaskProcessor) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.stopped {
+		p.stopped = true
+		close(p.quit)
+	}
+}
+
+// begin adds the task on the wait group unless the processor is stopped,
+// so wait group never gets added while it is being waited on.
+func (p *TaskProcessor) begin(wg *sync.WaitGroup) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped {
+		return false
+	}
+	wg.Add(1)
+	return true
+}
+
+// requeue returns the task to pending status using a fresh context since
+// the processing context is already cancelled.
+func (p *TaskProcessor) requeue(task dotagiftx.Task, cause error) {
+	ctx, cancel := context.WithTimeout(context.Background(), taskRequeueTimeout)
+	defer cancel()
+
+	task.Status = dotagiftx.TaskStatusPending
+	task.Note = fmt.Sprintf("requeued: %s", cause)
+	if err := p.queue.Update(ctx, task); err != nil {
+		log.Printf("ERR! could not requeue task: %s", err)
+	}
+}
+
 func (p *TaskProcessor) taskVerifyInventory(ctx context.Context, data interface{}) error {
-	var market dotagiftx.Market
-	if err := marshallTaskPayload(data, &market); err != nil {
+	var payload dotagiftx.VerifyInventoryPayload
+	if err := decodeTaskPayload(data, &payload); err != nil {
 		return err
 	}
-	if market.User == nil || market.Item == nil {
-		return fmt.Errorf("skipped process! missing data user:%#v item:%#v", market.User, market.Item)
+	market, err := p.market(payload.MarketID)
+	if err != nil {
+		return err
 	}
 	// Skips resold items.
 	if market.IsResell() {
@@ -112,7 +197,7 @@ func (p *TaskProcessor) taskVerifyInventory(ctx context.Context, data interface{
 	}
 
 	start := time.Now()
-	result, err := p.verify.Inventory(ctx, market.User.SteamID, market.Item.Name)
+	result, err := p.verify.Inventory(ctx, market.User.SteamID, *market.Item)
 	if err != nil {
 		return err
 	}
@@ -121,24 +206,31 @@ func (p *TaskProcessor) taskVerifyInventory(ctx context.Context, data interface{
 		Status:     result.Status,
 		Assets:     result.Assets,
 		VerifiedBy: result.VerifiedBy,
+		Confidence: result.Confidence,
+		Evidence:   result.Evidence,
 		ElapsedMs:  time.Since(start).Milliseconds(),
 	})
 }
 
 func (p *TaskProcessor) taskVerifyDelivery(ctx context.Context, data interface{}) error {
-	var market dotagiftx.Market
-	if err := marshallTaskPayload(data, &market); err != nil {
+	var payload dotagiftx.VerifyDeliveryPayload
+	if err := decodeTaskPayload(data, &payload); err != nil {
 		return err
 	}
-	if market.User == nil || market.Item == nil {
-		return fmt.Errorf("skipped process! missing data user:%#v item:%#v", market.User, market.Item)
+	market, err := p.market(payload.MarketID)
+	if err != nil {
+		return err
 	}
-	if err := p.inventoryInvalidator.Invalidate(ctx, market.PartnerSteamID); err != nil {
+	if err = p.inventoryInvalidator.Invalidate(ctx, market.PartnerSteamID); err != nil {
 		return fmt.Errorf("invalidate inventory: %s", err)
 	}
+	// Seller inventory will be used to confirm ownership transfer.
+	if err = p.inventoryInvalidator.Invalidate(ctx, market.User.SteamID); err != nil {
+		return fmt.Errorf("invalidate seller inventory: %s", err)
+	}
 
 	start := time.Now()
-	result, err := p.verify.Delivery(ctx, market.User.Name, market.PartnerSteamID, market.Item.Name)
+	result, err := p.verify.MarketDelivery(ctx, market)
 	if err != nil {
 		return err
 	}
@@ -147,11 +239,33 @@ func (p *TaskProcessor) taskVerifyDelivery(ctx context.Context, data interface{}
 		Status:     result.Status,
 		Assets:     result.Assets,
 		VerifiedBy: result.VerifiedBy,
+		Confidence: result.Confidence,
+		Evidence:   result.Evidence,
 		ElapsedMs:  time.Since(start).Milliseconds(),
 	})
 	return err
 }
 
+// market reloads fresh market details including its user and item.
+func (p *TaskProcessor) market(id string) (*dotagiftx.Market, error) {
+	if id == "" {
+		return nil, fmt.Errorf("skipped process! missing market id")
+	}
+	market, err := p.marketStg.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("could not get market %s: %s", id, err)
+	}
+	item, err := p.itemStg.Get(market.ItemID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get item %s: %s", market.ItemID, err)
+	}
+	market.Item = item
+	if market.User == nil || market.User.ID == "" {
+		return nil, fmt.Errorf("skipped process! missing user on market %s", id)
+	}
+	return market, nil
+}
+
 type taskQueue interface {
 	Get(ctx context.Context) (*dotagiftx.Task, error)
 	Update(ctx context.Context, t dotagiftx.Task) error
@@ -161,7 +275,11 @@ type inventoryInvalidator interface {
 	Invalidate(ctx context.Context, steamID string) error
 }
 
-func marshallTaskPayload(in, out interface{}) error {
+// decodeTaskPayload decodes versioned task payload into out.
+//
+// Legacy payloads has no version and holds a whole Market snapshot, only
+// its ID will be used since details will be reloaded anyway.
+func decodeTaskPayload(in, out interface{}) error {
 	raw, ok := in.(map[string]interface{})
 	if !ok {
 		return fmt.Errorf("un-supported payload")
@@ -170,8 +288,24 @@ func marshallTaskPayload(in, out interface{}) error {
 	if err != nil {
 		return err
 	}
-	if err = json.Unmarshal(b, out); err != nil {
+
+	var header struct {
+		Version  int    `json:"version"`
+		LegacyID string `json:"id"`
+	}
+	if err = json.Unmarshal(b, &header); err != nil {
 		return err
 	}
-	return nil
+	switch header.Version {
+	case dotagiftx.TaskPayloadVersion:
+	case 0:
+		b, err = json.Marshal(map[string]interface{}{"market_id": header.LegacyID})
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: %d", errTaskPayloadVersion, header.Version)
+	}
+
+	return json.Unmarshal(b, out)
 }
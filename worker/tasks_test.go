@@ -0,0 +1,180 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kudarap/dotagiftx"
+)
+
+// fakeTaskQueue serves the tasks once and records the last update of each.
+type fakeTaskQueue struct {
+	mu      sync.Mutex
+	pending []dotagiftx.Task
+	updates map[string]dotagiftx.Task
+	ctxErrs map[string]error
+}
+
+func newFakeTaskQueue(tasks ...dotagiftx.Task) *fakeTaskQueue {
+	return &fakeTaskQueue{pending: tasks, updates: map[string]dotagiftx.Task{}, ctxErrs: map[string]error{}}
+}
+
+func (q *fakeTaskQueue) Get(context.Context) (*dotagiftx.Task, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) == 0 {
+		return nil, nil
+	}
+	t := q.pending[0]
+	q.pending = q.pending[1:]
+	return &t, nil
+}
+
+func (q *fakeTaskQueue) Update(ctx context.Context, t dotagiftx.Task) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.updates[t.ID] = t
+	q.ctxErrs[t.ID] = ctx.Err()
+	return ctx.Err()
+}
+
+func (q *fakeTaskQueue) status(id string) dotagiftx.TaskStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.updates[id].Status
+}
+
+func newTestTaskProcessor(q taskQueue, run taskHandler) *TaskProcessor {
+	return &TaskProcessor{
+		queue:    q,
+		rate:     time.Millisecond,
+		quit:     make(chan struct{}),
+		handlers: map[dotagiftx.TaskType]taskHandler{dotagiftx.TaskTypeVerifyInventory: run},
+	}
+}
+
+func TestTaskProcessor_drain(t *testing.T) {
+	started := make(chan struct{}, 2)
+	tests := []struct {
+		name string
+		run  taskHandler
+		want dotagiftx.TaskStatus
+	}{
+		{"cancelled task requeued", func(ctx context.Context, _ interface{}) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			return ctx.Err()
+		}, dotagiftx.TaskStatusPending},
+		{"finished after cancel saved as done", func(ctx context.Context, _ interface{}) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			return nil
+		}, dotagiftx.TaskStatusDone},
+		{"failed task saved as error", func(ctx context.Context, _ interface{}) error {
+			started <- struct{}{}
+			return errors.New("bad payload")
+		}, dotagiftx.TaskStatusError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newFakeTaskQueue(dotagiftx.Task{ID: "1", Type: dotagiftx.TaskTypeVerifyInventory})
+			p := newTestTaskProcessor(q, tt.run)
+			ctx, cancel := context.WithCancel(context.Background())
+			var wg sync.WaitGroup
+			done := make(chan struct{})
+			go func() {
+				p.Run(ctx, &wg)
+				close(done)
+			}()
+
+			<-started
+			p.Stop()
+			cancel()
+			wg.Wait()
+			<-done
+
+			if got := q.status("1"); got != tt.want {
+				t.Errorf("task status = %v, want %v", got, tt.want)
+			}
+			if err := q.ctxErrs["1"]; err != nil {
+				t.Errorf("task updated with cancelled context: %s", err)
+			}
+		})
+	}
+}
+
+func TestTaskProcessor_stopBeforeTask(t *testing.T) {
+	q := newFakeTaskQueue()
+	p := newTestTaskProcessor(q, func(context.Context, interface{}) error { return nil })
+	p.Stop()
+	// stopping twice is safe.
+	p.Stop()
+
+	q.pending = []dotagiftx.Task{{ID: "1", Type: dotagiftx.TaskTypeVerifyInventory}}
+	var wg sync.WaitGroup
+	if p.begin(&wg) {
+		t.Fatal("begin() should not add task after stop")
+	}
+	p.Run(context.Background(), &wg)
+	wg.Wait()
+	if _, ok := q.updates["1"]; ok {
+		t.Error("task should not be processed after stop")
+	}
+}
+
+func Test_decodeTaskPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      interface{}
+		want    dotagiftx.VerifyDeliveryPayload
+		wantErr error
+	}{
+		{
+			"legacy v0 market snapshot",
+			map[string]interface{}{"id": "m1", "user_id": "u1", "status": float64(200)},
+			dotagiftx.VerifyDeliveryPayload{MarketID: "m1"},
+			nil,
+		},
+		{
+			"v1 payload",
+			map[string]interface{}{"version": float64(1), "market_id": "m1"},
+			dotagiftx.VerifyDeliveryPayload{Version: 1, MarketID: "m1"},
+			nil,
+		},
+		{
+			"v1 payload ignores legacy id",
+			map[string]interface{}{"version": float64(1), "market_id": "m1", "id": "m2"},
+			dotagiftx.VerifyDeliveryPayload{Version: 1, MarketID: "m1"},
+			nil,
+		},
+		{
+			"unknown version",
+			map[string]interface{}{"version": float64(99), "market_id": "m1"},
+			dotagiftx.VerifyDeliveryPayload{},
+			errTaskPayloadVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got dotagiftx.VerifyDeliveryPayload
+			err := decodeTaskPayload(tt.in, &got)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("decodeTaskPayload() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("decodeTaskPayload() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	var got dotagiftx.VerifyDeliveryPayload
+	if err := decodeTaskPayload(dotagiftx.Market{ID: "m1"}, &got); err == nil {
+		t.Error("decodeTaskPayload() non-map payload should fail")
+	}
+}
@@ -10,6 +10,10 @@ import (
 	"github.com/kudarap/dotagiftx/tracing"
 )
 
+// defaultDrainTimeout is the max duration to wait for running jobs and tasks
+// to finish before cancelling them on Stop.
+const defaultDrainTimeout = time.Second * 30
+
 // Worker represents worker handling and running tasks.
 type Worker struct {
 	wg       sync.WaitGroup
@@ -19,6 +23,12 @@ type Worker struct {
 	closed   bool
 	taskProc *TaskProcessor
 
+	// ctx is passed down to running jobs and tasks and only gets
+	// cancelled when they could not finish within drainTimeout.
+	ctx          context.Context
+	cancel       context.CancelFunc
+	drainTimeout time.Duration
+
 	logger logging.Logger
 	tracer *tracing.Tracer
 }
@@ -51,6 +61,8 @@ func New(tp *TaskProcessor, jobs ...Job) *Worker {
 	w.jobs = jobs
 	w.taskProc = tp
 	w.logger = logging.Default()
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.drainTimeout = defaultDrainTimeout
 	return w
 }
 
@@ -59,17 +71,20 @@ func (w *Worker) SetLogger(l logging.Logger) {
 	w.logger = l
 }
 
+// SetDrainTimeout overrides default drain timeout on Stop.
+func (w *Worker) SetDrainTimeout(d time.Duration) {
+	w.drainTimeout = d
+}
+
 // Start initiates worker to start running the jobs.
 //
 // All assigned jobs will be run concurrently.
 func (w *Worker) Start() {
 	w.logger.Infof("running task processor...")
-	go w.taskProc.Run(&w.wg)
+	go w.taskProc.Run(w.ctx, &w.wg)
 
 	w.logger.Infof("running jobs...")
 
-	ctx := context.Background()
-
 	// Queue initial registered jobs.
 	for _, jj := range w.jobs {
 		w.queueJob(jj, true)
@@ -88,7 +103,7 @@ func (w *Worker) Start() {
 			}
 
 			// Runner will block until done making it a single tasking worker.
-			w.runner(ctx, job)
+			w.runner(w.ctx, job)
 		}
 	}
 }
@@ -151,20 +166,48 @@ func (w *Worker) queueJob(j Job, now bool) {
 	// follow its own interval.
 	go func() {
 		if !now {
-			time.Sleep(j.Interval())
+			select {
+			case <-w.quit:
+				return
+			case <-time.After(j.Interval()):
+			}
 		}
 		w.logger.Printf("TODO job:%s", j)
-		w.queue <- j
+		select {
+		case <-w.quit:
+		case w.queue <- j:
+		}
 	}()
 }
 
-// Stop will stop accepting job and wait for processing job to finish.
+// Stop will stop accepting job and wait for processing job and task to finish.
+//
+// Running jobs and tasks that could not finish within the drain timeout
+// will have their context cancelled.
 func (w *Worker) Stop() error {
 	w.logger.Infof("stopping and waiting for jobs to finish...")
 	w.closed = true
-	w.quit <- struct{}{}
-	w.wg.Wait()
-	w.logger.Infof("all jobs done!")
+	close(w.quit)
+	w.taskProc.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		w.cancel()
+		w.logger.Infof("all jobs done!")
+		return nil
+	case <-time.After(w.drainTimeout):
+	}
+
+	w.logger.Warnf("drain timeout reached after %s, cancelling running jobs...", w.drainTimeout)
+	w.cancel()
+	<-done
+	w.logger.Infof("all jobs cancelled!")
 	return nil
 }
 